@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// corpus holds the payloads that the benchmarks read
+type corpus struct {
+	// fault is a fault with a chain of causes
+	fault []byte
+	// field is an object with the fault in the FaultField member
+	field []byte
+	// array is an object with faults in the Faults member
+	array []byte
+}
+
+// newCorpus synthesizes the payloads. The faults cycle through the kinds and
+// each has causeDepth nested causes. Members are in the order the bindings
+// write them so marshaling a decoded fault reproduces the payload.
+func newCorpus(nodes []*node, causeDepth, arraySize int) *corpus {
+	c := &corpus{}
+	c.fault = appendFault(nil, nodes, 0, causeDepth)
+
+	c.field = append(c.field, `{"FaultField":`...)
+	c.field = appendFault(c.field, nodes, 0, causeDepth)
+	c.field = append(c.field, '}')
+
+	c.array = append(c.array, `{"Faults":[`...)
+	for i := 0; i < arraySize; i++ {
+		if i > 0 {
+			c.array = append(c.array, ',')
+		}
+		c.array = appendFault(c.array, nodes, i, causeDepth)
+	}
+	c.array = append(c.array, "]}"...)
+	return c
+}
+
+// appendFault appends the fault at index and its causes. Without synthesized
+// kinds the faults are NotFound.
+func appendFault(buf []byte, nodes []*node, index, causeDepth int) []byte {
+	var n *node
+	kind := "NotFound"
+	if len(nodes) > 0 {
+		n = nodes[index%len(nodes)]
+		kind = n.Kind
+	}
+	suffix := strconv.Itoa(index)
+	buf = append(buf, `{"Kind":`...)
+	buf = appendString(buf, kind)
+	buf = append(buf, `,"Message":`...)
+	buf = appendString(buf, "message "+suffix)
+	buf = append(buf, `,"Cause":`...)
+	if causeDepth > 0 {
+		buf = appendFault(buf, nodes, index+1, causeDepth-1)
+	} else {
+		buf = append(buf, "null"...)
+	}
+	buf = append(buf, `,"ObjKind":"VirtualMachine","Obj":`...)
+	buf = appendString(buf, "vm-"+suffix)
+	if n != nil {
+		for _, field := range n.Fields {
+			buf = append(buf, ',')
+			buf = appendString(buf, field)
+			buf = append(buf, ':')
+			buf = appendString(buf, "detail "+suffix)
+		}
+	}
+	return append(buf, '}')
+}
+
+func appendString(buf []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(buf, b...)
+}
+
+// write stores the payloads in dir
+func (c *corpus) write(dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{
+		"fault.json": c.fault,
+		"field.json": c.field,
+		"array.json": c.array,
+	}
+	for name, data := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), data, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,587 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// modulePath is the module of the bindings that the generated module imports
+const modulePath = "github.com/karaatanassov/go_polymorphic_json"
+
+// strategy describes how a binding package names and decodes its types
+type strategy struct {
+	Package string
+	// FaultInterface is the interface that all faults implement
+	FaultInterface string
+	// Accessors tells that the kinds have interfaces with getters and setters
+	// and structs with the Struct suffix
+	Accessors bool
+	// Proxy tells that causes are read with FaultField through encoding/json
+	// instead of from the polymorphic.Value index
+	Proxy bool
+}
+
+var strategies = []strategy{
+	{Package: "utility_field", FaultInterface: "Fault", Accessors: true, Proxy: true},
+	{Package: "raw_message", FaultInterface: "Fault", Accessors: true},
+	{Package: "no_accessors", FaultInterface: "BaseFault"},
+}
+
+// typeView names the Go types of a kind in a strategy
+type typeView struct {
+	*node
+	Struct          string
+	Interface       string
+	ParentStruct    string
+	ParentInterface string
+	// Marker seals the interface of the kind
+	Marker string
+}
+
+func (s strategy) structName(kind string) string {
+	if s.Accessors {
+		return kind + "Struct"
+	}
+	return kind
+}
+
+func (s strategy) interfaceName(kind string) string {
+	if s.Accessors {
+		return kind
+	}
+	return "Base" + kind
+}
+
+// RuntimeStruct is the struct of RuntimeFault in the strategy
+func (s strategy) RuntimeStruct() string {
+	return s.structName("RuntimeFault")
+}
+
+func (s strategy) views(nodes []*node) []typeView {
+	views := make([]typeView, 0, len(nodes))
+	for _, n := range nodes {
+		views = append(views, typeView{
+			node:            n,
+			Struct:          s.structName(n.Kind),
+			Interface:       s.interfaceName(n.Kind),
+			ParentStruct:    s.structName(n.ParentKind()),
+			ParentInterface: s.interfaceName(n.ParentKind()),
+			Marker:          strings.ToLower(n.Kind[:1]) + n.Kind[1:],
+		})
+	}
+	return views
+}
+
+// generate writes a module to out that holds a copy of each binding package
+// extended with the synthesized kinds and a program that benchmarks them. The
+// module resolves the polymorphic package from the repository in repo.
+func generate(out, repo string, nodes []*node) error {
+	err := os.MkdirAll(out, 0755)
+	if err != nil {
+		return err
+	}
+	goMod := fmt.Sprintf("module polybench\n\ngo 1.15\n\nrequire %s v0.0.0\n\nreplace %s => %s\n",
+		modulePath, modulePath, repo)
+	err = ioutil.WriteFile(filepath.Join(out, "go.mod"), []byte(goMod), 0644)
+	if err != nil {
+		return err
+	}
+	err = writeSource(filepath.Join(out, "main.go"), driverTemplate, strategies)
+	if err != nil {
+		return err
+	}
+	for _, s := range strategies {
+		dir := filepath.Join(out, s.Package)
+		err = copyPackage(filepath.Join(repo, s.Package), dir)
+		if err != nil {
+			return err
+		}
+		err = writeSource(filepath.Join(dir, "bench.go"), benchTemplate, s)
+		if err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+		err = writeSource(filepath.Join(dir, "hierarchy.go"), typesTemplate, struct {
+			strategy
+			Types []typeView
+		}{s, s.views(nodes)})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyPackage copies the sources of a binding package without its tests
+func copyPackage(from, to string) error {
+	err := os.MkdirAll(to, 0755)
+	if err != nil {
+		return err
+	}
+	files, err := ioutil.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := ioutil.ReadFile(filepath.Join(from, name))
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(to, name), src, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSource executes the template and writes the formatted source
+func writeSource(path string, t *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	err := t.Execute(&buf, data)
+	if err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("cannot format %s: %v", path, err)
+	}
+	return ioutil.WriteFile(path, src, 0644)
+}
+
+var typesTemplate = template.Must(template.New("types").Parse(`// Code generated by polybench. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+{{$s := .}}
+{{- range .Types}}
+// {{.Interface}} is a synthesized kind that extends {{.ParentKind}}
+type {{.Interface}} interface {
+	{{.ParentInterface}}
+{{- if $s.Accessors}}
+	Get{{.Field}}() string
+	Set{{.Field}}(string)
+	{{.Marker}}()
+{{- else}}
+	Get{{.Kind}}() *{{.Struct}}
+{{- end}}
+}
+
+// {{.Struct}} adds {{.Field}} to {{.ParentStruct}}
+type {{.Struct}} struct {
+	{{.ParentStruct}}
+	{{.Field}} string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "{{.Kind}}",
+		Parent: "{{.ParentKind}}",
+		New:    func() interface{} { return &{{.Struct}}{} },
+	})
+}
+
+var _ {{.Interface}} = &{{.Struct}}{}
+var _ json.Marshaler = &{{.Struct}}{}
+var _ json.Unmarshaler = &{{.Struct}}{}
+
+// New{{.Kind}} creates {{.Kind}} with {{.Field}}. The options set the inherited
+// members.
+func New{{.Kind}}(value string, opts ...Option) *{{.Struct}} {
+	x := &{{.Struct}}{ {{- .Field}}: value}
+{{- if $s.Accessors}}
+	apply(&x.FaultStruct, opts)
+{{- else}}
+	apply(x.GetFault(), opts)
+{{- end}}
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (x *{{.Struct}}) GetKind() string {
+	return "{{.Kind}}"
+}
+{{if $s.Accessors}}
+func (x *{{.Struct}}) {{.Marker}}() {
+}
+
+// Get{{.Field}} returns {{.Field}}
+func (x *{{.Struct}}) Get{{.Field}}() string {
+	return x.{{.Field}}
+}
+
+// Set{{.Field}} sets {{.Field}}
+func (x *{{.Struct}}) Set{{.Field}}(value string) {
+	x.{{.Field}} = value
+}
+{{else}}
+func (x *{{.Struct}}) Get{{.Kind}}() *{{.Struct}} {
+	return x
+}
+{{end}}
+// MarshalJSON writes {{.Kind}} as JSON
+func (x *{{.Struct}}) MarshalJSON() ([]byte, error) {
+	return marshal(x)
+}
+
+func (x *{{.Struct}}) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "{{.Kind}}")
+	if err != nil {
+		return err
+	}
+	err = x.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.EndObject()
+	return nil
+}
+
+func (x *{{.Struct}}) encodeMembers(e *polymorphic.Encoder) error {
+{{- if .Parent}}
+	err := x.{{.ParentStruct}}.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+{{- else}}
+	err := x.{{$s.RuntimeStruct}}.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(x.ObjKind)
+	e.Name("Obj")
+	e.String(x.Obj)
+{{- end}}
+	e.Name("{{.Field}}")
+	e.String(x.{{.Field}})
+	return nil
+}
+{{if $s.Proxy}}
+// UnmarshalJSON reads {{.Kind}} from JSON
+func (x *{{.Struct}}) UnmarshalJSON(in []byte) error {
+	return x.unmarshal(in, false)
+}
+
+func (x *{{.Struct}}) unmarshal(in []byte, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         FaultField
+		Causes        FaultsField
+		ObjKind       string
+		Obj           string
+{{- range .Fields}}
+		{{.}} string
+{{- end}}
+	}{}
+	pxy.Cause.strict = strict
+	pxy.Causes.strict = strict
+	err := unmarshalProxy(in, pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "{{.Kind}}", strict)
+	if err != nil {
+		return err
+	}
+	x.Message = pxy.Message
+	x.MessageKey = pxy.MessageKey
+	x.MessageArgs = pxy.MessageArgs
+	x.Cause = pxy.Cause.Fault
+	x.Causes = pxy.Causes.Faults
+{{- else}}
+// UnmarshalJSON reads {{.Kind}} from JSON
+func (x *{{.Struct}}) UnmarshalJSON(in []byte) error {
+	return unmarshal(x, in, false)
+}
+
+func (x *{{.Struct}}) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		ObjKind       string
+		Obj           string
+{{- range .Fields}}
+		{{.}} string
+{{- end}}
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "{{.Kind}}", strict)
+	if err != nil {
+		return err
+	}
+	var cause {{$s.FaultInterface}}
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	x.Message = pxy.Message
+	x.MessageKey = pxy.MessageKey
+	x.MessageArgs = pxy.MessageArgs
+	x.Cause = cause
+	x.Causes = causes
+{{- end}}
+	x.ObjKind = pxy.ObjKind
+	x.Obj = pxy.Obj
+{{- range .Fields}}
+	x.{{.}} = pxy.{{.}}
+{{- end}}
+	return nil
+}
+{{end}}`))
+
+var benchTemplate = template.Must(template.New("bench").Parse(`// Code generated by polybench. DO NOT EDIT.
+
+package {{.Package}}
+
+import "encoding/json"
+
+// BenchUnmarshal reads a fault
+func BenchUnmarshal(in []byte) (interface{}, error) {
+	return UnmarshalFault(in)
+}
+
+// BenchMarshal writes a fault read by BenchUnmarshal
+func BenchMarshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// BenchUnmarshalField reads an object with a fault in the FaultField member
+func BenchUnmarshalField(in []byte) error {
+	return json.Unmarshal(in, &benchContainer{})
+}
+
+// BenchUnmarshalArray reads an object with faults in the Faults member
+func BenchUnmarshalArray(in []byte) error {
+	return json.Unmarshal(in, &benchArrayContainer{})
+}
+
+type benchContainer struct {
+	FaultField {{.FaultInterface}}
+}
+
+type benchArrayContainer struct {
+	Faults []{{.FaultInterface}}
+}
+{{if .Proxy}}
+func (c *benchContainer) UnmarshalJSON(in []byte) error {
+	temp := struct {
+		FaultField FaultField
+	}{}
+	err := json.Unmarshal(in, &temp)
+	if err != nil {
+		return err
+	}
+	c.FaultField = temp.FaultField.Fault
+	return nil
+}
+
+func (c *benchArrayContainer) UnmarshalJSON(in []byte) error {
+	temp := struct {
+		Faults []FaultField
+	}{}
+	err := json.Unmarshal(in, &temp)
+	if err != nil {
+		return err
+	}
+	c.Faults = ToFaultsArray(temp.Faults)
+	return nil
+}
+{{else}}
+func (c *benchContainer) UnmarshalJSON(in []byte) error {
+	temp := struct {
+		FaultField json.RawMessage
+	}{}
+	err := json.Unmarshal(in, &temp)
+	if err != nil {
+		return err
+	}
+	c.FaultField, err = UnmarshalFault(temp.FaultField)
+	return err
+}
+
+func (c *benchArrayContainer) UnmarshalJSON(in []byte) error {
+	temp := struct {
+		Faults []json.RawMessage
+	}{}
+	err := json.Unmarshal(in, &temp)
+	if err != nil {
+		return err
+	}
+	c.Faults = make([]{{.FaultInterface}}, 0, len(temp.Faults))
+	for _, raw := range temp.Faults {
+		fault, err := UnmarshalFault(raw)
+		if err != nil {
+			return err
+		}
+		c.Faults = append(c.Faults, fault)
+	}
+	return nil
+}
+{{end}}`))
+
+var driverTemplate = template.Must(template.New("driver").Parse(`// Code generated by polybench. DO NOT EDIT.
+
+// Command polybench reports the throughput and allocations of the binding
+// strategies on the synthesized corpus.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/tabwriter"
+{{range .}}
+	"polybench/{{.Package}}"
+{{- end}}
+)
+
+type strategy struct {
+	name      string
+	unmarshal func([]byte) (interface{}, error)
+	marshal   func(interface{}) ([]byte, error)
+	field     func([]byte) error
+	array     func([]byte) error
+}
+
+var strategies = []strategy{
+{{- range .}}
+	{"{{.Package}}", {{.Package}}.BenchUnmarshal, {{.Package}}.BenchMarshal, {{.Package}}.BenchUnmarshalField, {{.Package}}.BenchUnmarshalArray},
+{{- end}}
+}
+
+func main() {
+	testing.Init()
+	corpus := flag.String("corpus", "corpus", "directory with the payloads")
+	benchtime := flag.String("benchtime", "1s", "duration or iteration count of each benchmark")
+	flag.Parse()
+	err := flag.Set("test.benchtime", *benchtime)
+	if err == nil {
+		err = run(*corpus)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "polybench:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string) error {
+	payloads := map[string][]byte{}
+	for _, name := range []string{"fault", "field", "array"} {
+		in, err := ioutil.ReadFile(filepath.Join(dir, name+".json"))
+		if err != nil {
+			return err
+		}
+		payloads[name] = in
+	}
+	fault := payloads["fault"]
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "strategy\top\tns/op\tMB/s\tB/op\tallocs/op")
+	for _, s := range strategies {
+		s := s
+		v, err := s.unmarshal(fault)
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.name, err)
+		}
+		out, err := s.marshal(v)
+		if err != nil {
+			return fmt.Errorf("%s: %v", s.name, err)
+		}
+		if !bytes.Equal(out, fault) {
+			return fmt.Errorf("%s: marshaling the fault does not reproduce the corpus", s.name)
+		}
+		ops := []struct {
+			name string
+			in   []byte
+			run  func() error
+		}{
+			{"marshal", fault, func() error {
+				_, err := s.marshal(v)
+				return err
+			}},
+			{"unmarshal", fault, func() error {
+				_, err := s.unmarshal(fault)
+				return err
+			}},
+			{"field", payloads["field"], func() error {
+				return s.field(payloads["field"])
+			}},
+			{"array", payloads["array"], func() error {
+				return s.array(payloads["array"])
+			}},
+		}
+		for _, op := range ops {
+			err = report(w, s.name, op.name, op.in, op.run)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// report benchmarks op and prints a row of the table
+func report(w io.Writer, name, op string, in []byte, run func() error) error {
+	err := run()
+	if err != nil {
+		return fmt.Errorf("%s %s: %v", name, op, err)
+	}
+	r := testing.Benchmark(func(b *testing.B) {
+		b.SetBytes(int64(len(in)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			err := run()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	mbs := 0.0
+	if seconds := r.T.Seconds(); seconds > 0 {
+		mbs = float64(r.Bytes) * float64(r.N) / 1e6 / seconds
+	}
+	_, err = fmt.Fprintf(w, "%s\t%s\t%d\t%.2f\t%d\t%d\n",
+		name, op, r.NsPerOp(), mbs, r.AllocedBytesPerOp(), r.AllocsPerOp())
+	return err
+}
+`))
@@ -0,0 +1,64 @@
+package main
+
+import "strconv"
+
+// node is a synthesized kind. Each kind adds one string member to its parent.
+// The kinds at the top of the hierarchy extend NotFound.
+type node struct {
+	Kind   string
+	Parent *node
+	Field  string
+	// Fields lists the synthesized members from the top of the hierarchy down
+	// to the kind
+	Fields []string
+}
+
+// ParentKind returns the kind that the node extends
+func (n *node) ParentKind() string {
+	if n.Parent == nil {
+		return "NotFound"
+	}
+	return n.Parent.Kind
+}
+
+// hierarchy synthesizes width kinds extending NotFound and width kinds
+// extending each of them down to depth levels. Parents come before their
+// children.
+func hierarchy(width, depth int) []*node {
+	var nodes []*node
+	var grow func(parent *node, path string, level int)
+	grow = func(parent *node, path string, level int) {
+		if level > depth {
+			return
+		}
+		for i := 0; i < width; i++ {
+			suffix := strconv.Itoa(i)
+			if path != "" {
+				suffix = path + "_" + suffix
+			}
+			n := &node{Kind: "Gen" + suffix, Parent: parent, Field: "Detail" + suffix}
+			if parent != nil {
+				n.Fields = append(n.Fields, parent.Fields...)
+			}
+			n.Fields = append(n.Fields, n.Field)
+			nodes = append(nodes, n)
+			grow(n, suffix, level+1)
+		}
+	}
+	grow(nil, "", 1)
+	return nodes
+}
+
+// countKinds returns the number of kinds hierarchy synthesizes without
+// building them. It stops counting once the count exceeds limit.
+func countKinds(width, depth, limit int) int {
+	count, level := 0, 1
+	for i := 0; i < depth && count <= limit; i++ {
+		level *= width
+		if level > limit {
+			return limit + 1
+		}
+		count += level
+	}
+	return count
+}
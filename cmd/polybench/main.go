@@ -0,0 +1,97 @@
+// Command polybench compares the binding strategies on a synthesized
+// hierarchy. It generates a module with a copy of the utility_field,
+// raw_message and no_accessors packages extended with the same kinds, writes a
+// corpus of payloads and reports the throughput and allocations of marshal,
+// unmarshal, field and array decoding in each strategy.
+//
+// Run it from the root of the repository:
+//
+//	go run ./cmd/polybench -width 4 -depth 3 -cause-depth 8 -array-size 1000
+//
+// The hierarchy has width kinds extending NotFound and width kinds extending
+// each of them down to depth levels. Every kind adds one string member.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// maxKinds bounds the size of the synthesized hierarchy
+const maxKinds = 20000
+
+type options struct {
+	width, depth          int
+	causeDepth, arraySize int
+	out, repo, benchtime  string
+	generateOnly          bool
+}
+
+func main() {
+	var opts options
+	flag.IntVar(&opts.width, "width", 3, "kinds extending each kind of the hierarchy")
+	flag.IntVar(&opts.depth, "depth", 2, "levels of kinds below NotFound")
+	flag.IntVar(&opts.causeDepth, "cause-depth", 4, "nested causes of each fault in the corpus")
+	flag.IntVar(&opts.arraySize, "array-size", 100, "faults in the array payload")
+	flag.StringVar(&opts.out, "out", "", "directory for the generated module; a temporary one is removed after the run")
+	flag.StringVar(&opts.repo, "repo", ".", "root of the go_polymorphic_json repository")
+	flag.StringVar(&opts.benchtime, "benchtime", "1s", "duration or iteration count of each benchmark")
+	flag.BoolVar(&opts.generateOnly, "generate-only", false, "write the module and corpus without running the benchmarks")
+	flag.Parse()
+	err := run(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "polybench:", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts options) error {
+	if opts.width < 0 || opts.depth < 0 || opts.causeDepth < 0 || opts.arraySize < 0 {
+		return fmt.Errorf("width, depth, cause-depth and array-size cannot be negative")
+	}
+	if n := countKinds(opts.width, opts.depth, maxKinds); n > maxKinds {
+		return fmt.Errorf("the hierarchy exceeds %d kinds", maxKinds)
+	}
+	repo, err := filepath.Abs(opts.repo)
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(filepath.Join(repo, "polymorphic")); err != nil {
+		return fmt.Errorf("%s is not the root of the repository: %v", repo, err)
+	}
+	out := opts.out
+	if out == "" {
+		out, err = ioutil.TempDir("", "polybench")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(out)
+	}
+
+	nodes := hierarchy(opts.width, opts.depth)
+	err = generate(out, repo, nodes)
+	if err != nil {
+		return err
+	}
+	c := newCorpus(nodes, opts.causeDepth, opts.arraySize)
+	err = c.write(filepath.Join(out, "corpus"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d kinds, fault %d bytes, field %d bytes, array %d bytes\n",
+		len(nodes), len(c.fault), len(c.field), len(c.array))
+	if opts.generateOnly {
+		fmt.Println("generated", out)
+		return nil
+	}
+
+	cmd := exec.Command("go", "run", ".", "-corpus", "corpus", "-benchtime", opts.benchtime)
+	cmd.Dir = out
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
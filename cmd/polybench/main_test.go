@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestHierarchy(t *testing.T) {
+	nodes := hierarchy(3, 2)
+	if len(nodes) != 12 || countKinds(3, 2, maxKinds) != 12 {
+		t.Error("Unexpected number of kinds", len(nodes))
+		return
+	}
+	if nodes[0].Kind != "Gen0" || nodes[0].ParentKind() != "NotFound" {
+		t.Error("Unexpected top kind", nodes[0].Kind)
+	}
+	leaf := nodes[1]
+	if leaf.Kind != "Gen0_0" || leaf.ParentKind() != "Gen0" {
+		t.Error("Unexpected leaf kind", leaf.Kind, leaf.ParentKind())
+	}
+	if len(leaf.Fields) != 2 || leaf.Fields[0] != "Detail0" || leaf.Fields[1] != "Detail0_0" {
+		t.Error("Unexpected leaf fields", leaf.Fields)
+	}
+	if countKinds(100, 4, maxKinds) <= maxKinds {
+		t.Error("Expected the count to exceed the limit")
+	}
+}
+
+func TestCorpus(t *testing.T) {
+	c := newCorpus(hierarchy(2, 2), 3, 10)
+	array := &struct {
+		Faults []map[string]interface{}
+	}{}
+	err := json.Unmarshal(c.array, array)
+	if err != nil {
+		t.Error("Invalid array payload", err)
+		return
+	}
+	if len(array.Faults) != 10 || array.Faults[1]["Kind"] != "Gen0_0" {
+		t.Error("Unexpected array payload", string(c.array))
+	}
+	if !json.Valid(c.fault) || !json.Valid(c.field) {
+		t.Error("Invalid payloads", string(c.fault), string(c.field))
+	}
+	depth := 0
+	for cause := array.Faults[0]; cause != nil; depth++ {
+		cause, _ = cause["Cause"].(map[string]interface{})
+	}
+	if depth != 4 {
+		t.Error("Unexpected cause depth", depth)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Builds and runs the generated module")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("The go command is not available", err)
+	}
+	out, err := ioutil.TempDir("", "polybench")
+	if err != nil {
+		t.Error("Cannot create directory", err)
+		return
+	}
+	defer os.RemoveAll(out)
+	// The generated program fails if marshaling a decoded fault does not
+	// reproduce the corpus in any of the strategies.
+	err = run(options{
+		width:      2,
+		depth:      2,
+		causeDepth: 2,
+		arraySize:  4,
+		out:        out,
+		repo:       "../..",
+		benchtime:  "1x",
+	})
+	if err != nil {
+		t.Error("Benchmark run failed", err)
+	}
+}
@@ -0,0 +1,99 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// withAbstract runs test with a registry in which the given kinds are abstract
+func withAbstract(test func(), kinds ...string) {
+	r := polymorphic.NewRegistry()
+	for _, kind := range registry.Kinds() {
+		typ, _ := registry.Lookup(kind)
+		for _, abstract := range kinds {
+			typ.Abstract = typ.Abstract || kind == abstract
+		}
+		r.Register(typ)
+	}
+	saved := registry
+	registry = r
+	defer func() { registry = saved }()
+	test()
+}
+
+var concreteNotFound = &NotFound{
+	RuntimeFault: RuntimeFault{
+		Fault: Fault{
+			Message: "test message",
+			Cause:   &Fault{Message: "inner message"},
+		},
+	},
+	ObjKind: "VirtualMachine",
+	Obj:     "vm-42",
+}
+
+func TestAbstractMarshal(t *testing.T) {
+	withAbstract(func() {
+		if !IsAbstract("RuntimeFault") {
+			t.Error("Expected RuntimeFault to be abstract")
+		}
+		_, err := json.Marshal(runtimeFault)
+		if err == nil {
+			t.Error("Expected to fail writing abstract RuntimeFault")
+		}
+		t.Log("Marshal error", err)
+		// The inner cause is abstract RuntimeFault
+		_, err = json.Marshal(notFound)
+		if err == nil {
+			t.Error("Expected to fail writing abstract RuntimeFault cause")
+		}
+		_, err = json.Marshal(concreteNotFound)
+		if err != nil {
+			t.Error("Expected to write concrete NotFound", err)
+		}
+	}, "RuntimeFault")
+}
+
+func TestAbstractUnmarshal(t *testing.T) {
+	b, err := json.Marshal(runtimeFault)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	withAbstract(func() {
+		_, err := UnmarshalFault(b)
+		if err == nil {
+			t.Error("Expected to fail reading abstract RuntimeFault")
+		}
+		t.Log("Unmarshal error", err)
+		_, err = UnmarshalRuntimeFault(b)
+		if err == nil {
+			t.Error("Expected to fail narrowing abstract RuntimeFault")
+		}
+		rf := RuntimeFault{}
+		err = json.Unmarshal(b, &rf)
+		if err == nil {
+			t.Error("Expected to fail reading abstract RuntimeFault into struct")
+		}
+	}, "RuntimeFault")
+}
+
+func TestAbstractDescendant(t *testing.T) {
+	withAbstract(func() {
+		b, err := json.Marshal(concreteNotFound)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			return
+		}
+		rtf, err := UnmarshalRuntimeFault(b)
+		if err != nil {
+			t.Error("Expected to read NotFound as RuntimeFault", err)
+			return
+		}
+		if rtf.GetKind() != "NotFound" {
+			t.Error("Unexpected kind:", rtf.GetKind())
+		}
+	}, "RuntimeFault")
+}
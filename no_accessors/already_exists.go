@@ -0,0 +1,154 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// BaseAlreadyExists represents error when the object to create already exists
+type BaseAlreadyExists interface {
+	BaseRuntimeFault
+	GetAlreadyExists() *AlreadyExists
+}
+
+// AlreadyExists contains the data about an object that already exists
+type AlreadyExists struct {
+	RuntimeFault
+	ObjKind string
+	Obj     string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "AlreadyExists",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &AlreadyExists{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 409,
+			ExitCode:   73, // EX_CANTCREAT
+		},
+	})
+}
+
+var _ BaseAlreadyExists = &AlreadyExists{}
+var _ BaseRuntimeFault = &AlreadyExists{}
+var _ BaseFault = &AlreadyExists{}
+var _ json.Marshaler = &AlreadyExists{}
+var _ json.Unmarshaler = &AlreadyExists{}
+var _ fmt.Formatter = &AlreadyExists{}
+
+// NewAlreadyExists creates an AlreadyExists for the object of objKind with id
+// obj. The options set the members inherited from Fault like the message.
+func NewAlreadyExists(objKind, obj string, opts ...Option) *AlreadyExists {
+	x := &AlreadyExists{ObjKind: objKind, Obj: obj}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (ae *AlreadyExists) GetKind() string {
+	return "AlreadyExists"
+}
+
+// Error formats the fault with its kind, message, the object that already
+// exists and the cause
+func (ae *AlreadyExists) Error() string {
+	details := strings.TrimSpace(ae.ObjKind + " " + ae.Obj)
+	return formatError("AlreadyExists", ae.Message, details, ae.Cause, ae.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (ae *AlreadyExists) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, ae)
+}
+
+func (ae *AlreadyExists) GetAlreadyExists() *AlreadyExists {
+	return ae
+}
+
+// MarshalJSON writes AlreadyExists as JSON
+func (ae *AlreadyExists) MarshalJSON() ([]byte, error) {
+	return marshal(ae)
+}
+
+// encode writes the Kind first and then the members directly
+func (ae *AlreadyExists) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "AlreadyExists")
+	if err != nil {
+		return err
+	}
+	err = ae.RuntimeFault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(ae.ObjKind)
+	e.Name("Obj")
+	e.String(ae.Obj)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (ae *AlreadyExists) UnmarshalJSON(in []byte) error {
+	return unmarshal(ae, in, false)
+}
+
+func (ae *AlreadyExists) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		ObjKind       string
+		Obj           string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "AlreadyExists", strict)
+	if err != nil {
+		return err
+	}
+	var cause BaseFault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	ae.Message = pxy.Message
+	ae.MessageKey = pxy.MessageKey
+	ae.MessageArgs = pxy.MessageArgs
+	ae.Cause = cause
+	ae.Causes = causes
+	ae.ObjKind = pxy.ObjKind
+	ae.Obj = pxy.Obj
+	return nil
+}
+
+// UnmarshalAlreadyExists reads AlreadyExists or its subclasses from JSON bytes
+func UnmarshalAlreadyExists(in []byte) (BaseAlreadyExists, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyExists, ok := fault.(BaseAlreadyExists); ok {
+		return alreadyExists, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal AlreadyExists from %v", fault)
+}
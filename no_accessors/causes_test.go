@@ -0,0 +1,90 @@
+package no_accessors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// batchJSON aggregates the failures of a batch in Causes
+const batchJSON = `{"Kind":"RuntimeFault","Message":"batch failed","Cause":null,"Causes":[` +
+	notFoundJSON + `,null,{"Kind":"Fault","Message":"quota","Cause":null}]}`
+
+func TestCausesRoundTrip(t *testing.T) {
+	f, err := UnmarshalFault([]byte(batchJSON))
+	if err != nil {
+		t.Error("Cannot deserialize causes", err)
+		return
+	}
+	causes := f.GetFault().Causes
+	if len(causes) != 3 || causes[1] != nil || causes[2].GetFault().Message != "quota" {
+		t.Error("Unexpected causes", causes)
+		return
+	}
+	validateNotFound(causes[0], t)
+	b, err := json.Marshal(f)
+	if err != nil || string(b) != batchJSON {
+		t.Error("Unexpected JSON", string(b), err)
+	}
+	var buf bytes.Buffer
+	err = EncodeFault(&buf, f)
+	if err != nil || buf.String() != batchJSON {
+		t.Error("Unexpected encoding", buf.String(), err)
+	}
+}
+
+func TestCausesSingleCause(t *testing.T) {
+	for _, in := range []string{notFoundJSON, `{"Kind":"Fault","Message":"m","Cause":null,"Causes":null}`} {
+		f, err := UnmarshalFaultStrict([]byte(in))
+		if err != nil {
+			t.Error("Cannot deserialize payload without causes", in, err)
+			continue
+		}
+		if f.GetFault().Causes != nil {
+			t.Error("Expected no causes", f.GetFault().Causes)
+		}
+	}
+}
+
+func TestCausesStrict(t *testing.T) {
+	unknownMember := `{"Kind":"Fault","Message":"m","Causes":[{"Kind":"Fault","Unknown":1}]}`
+	_, err := UnmarshalFault([]byte(unknownMember))
+	if err != nil {
+		t.Error("Expected lenient decoding to ignore the member", err)
+	}
+	_, err = UnmarshalFaultStrict([]byte(unknownMember))
+	var elementErr *polymorphic.ElementError
+	if !errors.As(err, &elementErr) || elementErr.Index != 0 {
+		t.Error("Expected the unknown member of the cause to fail", err)
+	}
+	_, err = UnmarshalFault([]byte(`{"Kind":"Fault","Message":"m","Causes":{"Kind":"Fault"}}`))
+	if err == nil {
+		t.Error("Expected causes that are not an array to fail")
+	}
+}
+
+func TestCausesErrors(t *testing.T) {
+	batch := &Fault{Message: "batch failed", Causes: []BaseFault{fault, nil, notFound}}
+	err := fmt.Errorf("run: %w", batch)
+	var nf BaseNotFound
+	if !errors.As(err, &nf) || nf != notFound {
+		t.Error("Expected to find the NotFound in the causes", err)
+	}
+	if !errors.Is(err, &innerRuntimeFault) || errors.Is(err, io.EOF) {
+		t.Error("Unexpected errors in the causes", err)
+	}
+	expected := "Fault: batch failed [Fault: test message: RuntimeFault: inner message; <nil>; " +
+		"NotFound: test message (VirtualMachine vm-42): RuntimeFault: inner message]"
+	if batch.Error() != expected {
+		t.Error("Unexpected error string", batch.Error())
+	}
+	batch.Cause = &innerFault
+	if all := batch.UnwrapAll(); len(all) != 3 || all[0] != &innerFault || all[2] != notFound {
+		t.Error("Unexpected tree", all)
+	}
+}
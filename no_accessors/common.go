@@ -1,12 +1,115 @@
 package no_accessors
 
-import "reflect"
+import (
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
 
 var (
-	// t is a global type map for unmarhsaling polymorphic types
-	t map[string]reflect.Type
+	// registry holds the fault types for unmarshaling and introspection
+	registry = polymorphic.NewRegistry()
+	// discriminator names the property that selects the fault type
+	discriminator = polymorphic.Discriminator{KindProperty: "Kind"}
+	// migrations upgrade documents written with older schema versions
+	migrations = polymorphic.NewMigrator("Kind", "SchemaVersion")
 )
 
-func init() {
-	t = make(map[string]reflect.Type)
+// KindOf returns the kind of the fault or empty string if its type is not
+// registered.
+func KindOf(v BaseFault) string {
+	return registry.KindOf(v)
+}
+
+// IsA reports whether kind is ancestorKind or one of its descendants
+func IsA(kind, ancestorKind string) bool {
+	return registry.IsA(kind, ancestorKind)
+}
+
+// IsAbstract reports whether kind is abstract. Abstract kinds are never
+// written or read on the wire.
+func IsAbstract(kind string) bool {
+	return registry.IsAbstract(kind)
+}
+
+// Parent returns the kind that kind extends or false for the root
+func Parent(kind string) (string, bool) {
+	return registry.Parent(kind)
+}
+
+// Ancestors lists the kinds that kind extends starting with its parent
+func Ancestors(kind string) []string {
+	return registry.Ancestors(kind)
+}
+
+// Descendants lists all kinds that extend kind
+func Descendants(kind string) []string {
+	return registry.Descendants(kind)
+}
+
+// ClassifyKind returns the classification of kind. The members the kind does
+// not set are inherited from its ancestors.
+func ClassifyKind(kind string) polymorphic.Classification {
+	return registry.Classify(kind)
+}
+
+// Classify returns the classification of the kind of the fault. Types that
+// are not registered are classified by the kind they report with GetKind.
+func Classify(f BaseFault) polymorphic.Classification {
+	if isNil(f) {
+		return polymorphic.Classification{}
+	}
+	return registry.Classify(faultKind(f))
+}
+
+// faultKind returns the registered kind of the fault or the kind it reports
+func faultKind(f BaseFault) string {
+	kind := registry.KindOf(f)
+	if kind == "" {
+		kind = f.GetKind()
+	}
+	return kind
+}
+
+// IsRetryable reports whether the fault is transient so the operation that
+// failed may succeed when retried
+func IsRetryable(f BaseFault) bool {
+	return Classify(f).IsRetryable()
+}
+
+// RegisterMigration adds a migration that UnmarshalFault applies to documents
+// of the migration kind and schema version. Marshaling writes the schema
+// version reached by the migrations of the kind.
+func RegisterMigration(m polymorphic.Migration) {
+	migrations.Register(m)
+}
+
+// DowngradeFault reverts the migrations of the faults in the JSON so they are
+// at most at schemaVersion. It serves consumers that read older schemas.
+func DowngradeFault(in []byte, schemaVersion int) ([]byte, error) {
+	return migrations.Downgrade(in, schemaVersion)
+}
+
+// checkConcrete fails for abstract kinds that cannot be written on the wire
+func checkConcrete(kind string) error {
+	if registry.IsAbstract(kind) {
+		return fmt.Errorf("kind %q is abstract", kind)
+	}
+	return nil
+}
+
+// checkKind verifies the Kind read from the wire is not abstract. In strict
+// mode the kind also needs to be targetKind or one of its descendants.
+func checkKind(kind string, targetKind string, strict bool) error {
+	err := checkConcrete(kind)
+	if err != nil || !strict {
+		return err
+	}
+	if _, ok := registry.Lookup(kind); !ok {
+		return fmt.Errorf("unknown kind %q", kind)
+	}
+	if !IsA(kind, targetKind) {
+		return fmt.Errorf("kind %q is not %s", kind, targetKind)
+	}
+	return nil
 }
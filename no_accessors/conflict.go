@@ -0,0 +1,153 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// BaseConflict represents error when the request conflicts with the current
+// state of an object such as a concurrent modification
+type BaseConflict interface {
+	BaseRuntimeFault
+	GetConflict() *Conflict
+}
+
+// Conflict contains the data about a conflicting object
+type Conflict struct {
+	RuntimeFault
+	ObjKind string
+	Obj     string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "Conflict",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &Conflict{} },
+		Classification: polymorphic.Classification{
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 409,
+		},
+	})
+}
+
+var _ BaseConflict = &Conflict{}
+var _ BaseRuntimeFault = &Conflict{}
+var _ BaseFault = &Conflict{}
+var _ json.Marshaler = &Conflict{}
+var _ json.Unmarshaler = &Conflict{}
+var _ fmt.Formatter = &Conflict{}
+
+// NewConflict creates a Conflict for the object of objKind with id obj.
+// The options set the members inherited from Fault like the message.
+func NewConflict(objKind, obj string, opts ...Option) *Conflict {
+	x := &Conflict{ObjKind: objKind, Obj: obj}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (cf *Conflict) GetKind() string {
+	return "Conflict"
+}
+
+// Error formats the fault with its kind, message, the object in conflict
+// and the cause
+func (cf *Conflict) Error() string {
+	details := strings.TrimSpace(cf.ObjKind + " " + cf.Obj)
+	return formatError("Conflict", cf.Message, details, cf.Cause, cf.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (cf *Conflict) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, cf)
+}
+
+func (cf *Conflict) GetConflict() *Conflict {
+	return cf
+}
+
+// MarshalJSON writes Conflict as JSON
+func (cf *Conflict) MarshalJSON() ([]byte, error) {
+	return marshal(cf)
+}
+
+// encode writes the Kind first and then the members directly
+func (cf *Conflict) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "Conflict")
+	if err != nil {
+		return err
+	}
+	err = cf.RuntimeFault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(cf.ObjKind)
+	e.Name("Obj")
+	e.String(cf.Obj)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (cf *Conflict) UnmarshalJSON(in []byte) error {
+	return unmarshal(cf, in, false)
+}
+
+func (cf *Conflict) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		ObjKind       string
+		Obj           string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "Conflict", strict)
+	if err != nil {
+		return err
+	}
+	var cause BaseFault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	cf.Message = pxy.Message
+	cf.MessageKey = pxy.MessageKey
+	cf.MessageArgs = pxy.MessageArgs
+	cf.Cause = cause
+	cf.Causes = causes
+	cf.ObjKind = pxy.ObjKind
+	cf.Obj = pxy.Obj
+	return nil
+}
+
+// UnmarshalConflict reads Conflict or its subclasses from JSON bytes
+func UnmarshalConflict(in []byte) (BaseConflict, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if conflict, ok := fault.(BaseConflict); ok {
+		return conflict, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal Conflict from %v", fault)
+}
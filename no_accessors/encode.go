@@ -0,0 +1,95 @@
+package no_accessors
+
+import (
+	"io"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// encoder is implemented by all fault structs. They write their members into
+// the encoder after the discriminator.
+type encoder interface {
+	encode(e *polymorphic.Encoder) error
+}
+
+var _ encoder = &Fault{}
+var _ encoder = &RuntimeFault{}
+var _ encoder = &NotFound{}
+var _ encoder = &InvalidArgument{}
+var _ encoder = &AlreadyExists{}
+var _ encoder = &PermissionDenied{}
+var _ encoder = &Timeout{}
+var _ encoder = &Conflict{}
+var _ encoder = &ResourceExhausted{}
+
+// EncodeFault writes the fault as JSON to w. It writes Kind first and then the
+// members directly into a pooled buffer so a fault and its causes are written
+// without intermediate copies.
+func EncodeFault(w io.Writer, f BaseFault) error {
+	e := polymorphic.AcquireEncoder()
+	defer polymorphic.ReleaseEncoder(e)
+	err := encodeFault(e, f)
+	if err != nil {
+		return err
+	}
+	_, err = e.WriteTo(w)
+	return err
+}
+
+// marshal encodes the fault into a new slice for MarshalJSON
+func marshal(f encoder) ([]byte, error) {
+	e := polymorphic.AcquireEncoder()
+	defer polymorphic.ReleaseEncoder(e)
+	err := f.encode(e)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), e.Bytes()...), nil
+}
+
+// encodeFault writes the fault or null. Extensions and types that are not
+// registered write themselves with their MarshalJSON as the encode method
+// promoted from the embedded struct would drop their members.
+func encodeFault(e *polymorphic.Encoder, f BaseFault) error {
+	if isNil(f) {
+		e.Null()
+		return nil
+	}
+	typ, ok := registry.Lookup(registry.KindOf(f))
+	enc, isEncoder := f.(encoder)
+	if !ok || typ.Extension || !isEncoder {
+		return e.Marshal(f)
+	}
+	return enc.encode(e)
+}
+
+// encodeFaults writes the faults as a JSON array
+func encodeFaults(e *polymorphic.Encoder, faults []BaseFault) error {
+	e.BeginArray()
+	for _, f := range faults {
+		e.Element()
+		err := encodeFault(e, f)
+		if err != nil {
+			return err
+		}
+	}
+	e.EndArray()
+	return nil
+}
+
+// beginFault checks the kind can be written and starts the object with the
+// discriminator and the schema version.
+func beginFault(e *polymorphic.Encoder, kind string) error {
+	err := checkConcrete(kind)
+	if err != nil {
+		return err
+	}
+	e.BeginObject()
+	e.Name("Kind")
+	e.String(kind)
+	if version := migrations.Current(kind); version != 0 {
+		e.Name("SchemaVersion")
+		e.Int(version)
+	}
+	return nil
+}
@@ -9,13 +9,16 @@ package no_accessors
 import (
 	"encoding/json"
 	"fmt"
-	"reflect"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // BaseFault is implemented by Error struct and included in RuntimeFault and
 // NotFound. Thus one can upcast.
 // This should be generated code.
 type BaseFault interface {
+	error
+	GetKind() string
 	GetFault() *Fault
 }
 
@@ -24,98 +27,266 @@ type BaseFault interface {
 // and the JSONSerializable
 type Fault struct {
 	Message string
-	Cause   BaseFault
+	// MessageKey selects the template of the message in a
+	// polymorphic.MessageCatalog. Message is the default for clients without
+	// catalogs. The key and the arguments are written only when they are set.
+	MessageKey  string                   `json:",omitempty"`
+	MessageArgs []polymorphic.MessageArg `json:",omitempty"`
+	Cause       BaseFault
+	// Causes lists the failures aggregated by the fault such as the failed
+	// items of a batch. It is written only when it is not empty.
+	Causes []BaseFault `json:",omitempty"`
 }
 
 func init() {
-	t["Fault"] = reflect.TypeOf((*Fault)(nil)).Elem()
+	registry.Register(polymorphic.Type{
+		Kind: "Fault",
+		New:  func() interface{} { return &Fault{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityError,
+			HTTPStatus: 500,
+			ExitCode:   1,
+		},
+	})
 }
 
 var _ BaseFault = &Fault{}
 var _ json.Marshaler = &Fault{}
 var _ json.Unmarshaler = &Fault{}
+var _ fmt.Formatter = &Fault{}
+
+// NewFault creates a Fault.
+// The options set the members inherited from Fault like the message.
+func NewFault(opts ...Option) *Fault {
+	x := &Fault{}
+	apply(x, opts)
+	return x
+}
 
 // These assignments are not allowed
 //var _ RuntimeFault = &Fault{}
 //var _ NotFound = &Fault{}
 
+// GetKind returns the discriminator of the fault
+func (f *Fault) GetKind() string {
+	return "Fault"
+}
+
+// Error formats the fault with its kind, message and cause
+func (f *Fault) Error() string {
+	return formatError("Fault", f.Message, "", f.Cause, f.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (f *Fault) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, f)
+}
+
+// Unwrap returns the cause so errors.Is and errors.As walk the cause chain
+func (f *Fault) Unwrap() error {
+	return unwrapCause(f.Cause)
+}
+
+// UnwrapAll returns the cause followed by the causes for walking the whole
+// tree of failures
+func (f *Fault) UnwrapAll() []error {
+	return unwrapAll(f.Cause, f.Causes)
+}
+
+// Is reports whether target is in the chain of one of the causes. errors.Is
+// walks the chain of the cause through Unwrap.
+func (f *Fault) Is(target error) bool {
+	return isInCauses(f.Causes, target)
+}
+
+// As finds the first error in the chains of the causes that matches target.
+// errors.As walks the chain of the cause through Unwrap after the causes.
+func (f *Fault) As(target interface{}) bool {
+	return asInCauses(f.Causes, target)
+}
+
 func (f *Fault) GetFault() *Fault {
 	return f
 }
 
+// LocalizedMessage renders the message in locale with the catalog. It is
+// Message if the fault has no key or the catalog has no template for it.
+func (f *Fault) LocalizedMessage(c *polymorphic.MessageCatalog, locale string) string {
+	return c.Render(locale, f.MessageKey, f.MessageArgs, f.Message)
+}
+
 // UnmarshalJSON reads a fault from JSON
 func (fault *Fault) UnmarshalJSON(in []byte) error {
+	return unmarshal(fault, in, false)
+}
+
+func (fault *Fault) unmarshalValue(v *polymorphic.Value, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   json.RawMessage
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
 	}{}
-	err := json.Unmarshal(in, pxy)
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "Fault", strict)
 	if err != nil {
 		return err
 	}
 	var cause BaseFault
 	if pxy.Cause != nil {
-		cause, err = UnmarshalFault(pxy.Cause)
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
 		if err != nil {
 			return err
 		}
 	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
 	fault.Message = pxy.Message
+	fault.MessageKey = pxy.MessageKey
+	fault.MessageArgs = pxy.MessageArgs
 	fault.Cause = cause
+	fault.Causes = causes
 	return nil
 }
 
 // MarshalJSON writes Fault as JSON and adds discriminator
 func (fault *Fault) MarshalJSON() ([]byte, error) {
-	type marshalable Fault
-	// The approach below copies the full object into a temporary object
-	// with discriminator and passes it to the go json mashaler.
-	// An alternative is to create small utility object that holds the
-	// discriminator and anonymous Fault pointer. This requires the
-	// serialization to be in custom interface and additional copy logic in
-	// higher level bindings. The current approach preserves the go abstractions
-	// and simplifies higher level bindings.
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "Fault",
-		marshalable: marshalable(*fault),
-	})
+	return marshal(fault)
+}
+
+// encode writes the Kind first and then the members directly. Unlike the
+// json.Marshal of a struct that embeds the fault and adds the discriminator it
+// does not copy the fault and writes nested causes into the same buffer.
+func (fault *Fault) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "Fault")
+	if err != nil {
+		return err
+	}
+	err = fault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.EndObject()
+	return nil
+}
+
+// encodeMembers writes the members declared by Fault
+func (fault *Fault) encodeMembers(e *polymorphic.Encoder) error {
+	e.Name("Message")
+	e.String(fault.Message)
+	if fault.MessageKey != "" {
+		e.Name("MessageKey")
+		e.String(fault.MessageKey)
+	}
+	if len(fault.MessageArgs) > 0 {
+		e.Name("MessageArgs")
+		err := e.Marshal(fault.MessageArgs)
+		if err != nil {
+			return err
+		}
+	}
+	e.Name("Cause")
+	err := encodeFault(e, fault.Cause)
+	if err != nil || len(fault.Causes) == 0 {
+		return err
+	}
+	e.Name("Causes")
+	return encodeFaults(e, fault.Causes)
 }
 
 // UnmarshalFault reads a fault from JSON and instantiates the proper type
-// based on the Kind field. It deserializes the value twice. First scan for
-// discriminator and then deserializes into the proper type.
+// based on the Kind field. It scans for the discriminator and then
+// deserializes into the proper type.
 func UnmarshalFault(in []byte) (BaseFault, error) {
-	d := &struct {
-		Kind string
-	}{}
-	// Double pointer detects null values
-	err := json.Unmarshal(in, &d)
+	return unmarshalFault(in, false)
+}
+
+func unmarshalFault(in []byte, strict bool) (BaseFault, error) {
+	// The JSON is indexed once. Nested causes are read from the index so the
+	// bytes of a deep cause chain are not scanned again at every level.
+	v, err := polymorphic.Parse(in)
 	if err != nil {
 		return nil, err
 	}
-	if d == nil {
-		return nil, nil
-	}
-	kind := d.Kind
+	return unmarshalFaultValue(v, strict)
+}
 
-	reflectType, ok := t[kind]
+func unmarshalFaultValue(v *polymorphic.Value, strict bool) (BaseFault, error) {
+	kind, ok, err := discriminator.ValueKind(v)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		return nil, fmt.Errorf("unknown type %v", kind)
+		return nil, nil
+	}
+	// Documents written with an older schema are migrated to the current
+	// shape before they are read.
+	if migrations.Migrates(kind) {
+		in, upgraded, err := migrations.Upgrade(v.Raw, string(kind))
+		if err != nil {
+			return nil, err
+		}
+		v, err = polymorphic.Parse(in)
+		if err != nil {
+			return nil, err
+		}
+		kind = []byte(upgraded)
 	}
 
-	var res BaseFault
-	tv := reflect.New(reflectType)
-	if res, ok = tv.Interface().(BaseFault); !ok {
-		panic("A type in the registry does not implement Fault")
+	res := newFault(kind)
+	if res == nil {
+		return nil, fmt.Errorf("unknown type %s", kind)
 	}
 
-	err = json.Unmarshal(in, res)
+	err = res.(unmarshaler).unmarshalValue(v, strict)
 	if err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
+
+// unmarshalCauses reads the Causes array. Payloads without the member or with
+// null have no causes.
+func unmarshalCauses(v *polymorphic.Value, strict bool) ([]BaseFault, error) {
+	if v == nil || v.IsNull() {
+		return nil, nil
+	}
+	if !v.IsArray() {
+		return nil, fmt.Errorf("expected JSON array of causes")
+	}
+	elements := v.Elements()
+	causes := make([]BaseFault, len(elements))
+	for i := range elements {
+		cause, err := unmarshalFaultValue(&elements[i], strict)
+		if err != nil {
+			return nil, &polymorphic.ElementError{Index: i, Err: err}
+		}
+		causes[i] = cause
+	}
+	return causes, nil
+}
+
+// newFault instantiates the registered type for the kind bytes or returns nil
+// if the kind is unknown. The dispatch does not allocate a string for the kind.
+func newFault(kind []byte) BaseFault {
+	res := registry.NewBytes(kind)
+	if res == nil {
+		return nil
+	}
+	fault, ok := res.(BaseFault)
+	if !ok {
+		panic("A type in the registry does not implement Fault")
+	}
+	return fault
+}
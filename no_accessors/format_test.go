@@ -0,0 +1,82 @@
+package no_accessors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+func TestFormat(t *testing.T) {
+	nf := NewNotFound("Cat", "Lucie", WithMessage("The cat Lucie is missing"))
+	cases := map[string]string{
+		"%v": "NotFound: The cat Lucie is missing (Cat Lucie)",
+		"%s": "NotFound: The cat Lucie is missing (Cat Lucie)",
+		"%q": `"NotFound: The cat Lucie is missing (Cat Lucie)"`,
+		"%d": "%!d(NotFound: The cat Lucie is missing (Cat Lucie))",
+	}
+	for format, expected := range cases {
+		if s := fmt.Sprintf(format, nf); s != expected {
+			t.Errorf("Sprintf(%s) = %q expected %q", format, s, expected)
+		}
+	}
+	var nilFault *NotFound
+	if s := fmt.Sprintf("%+v", nilFault); s != "<nil>" {
+		t.Error("Unexpected nil fault", s)
+	}
+}
+
+func TestFormatCauseChain(t *testing.T) {
+	f := NewNotFound("Cat", "Lucie",
+		WithMessage("The cat Lucie is missing"),
+		WithMessageKey("cat.missing", polymorphic.MessageArg{Name: "name", Value: "Lucie"}),
+		WithCause(NewRuntimeFault(WithMessage("The shelter is closed"),
+			WithCause(NewTimeout("Open")))),
+		WithCauses(NewInvalidArgument("name"), nil))
+	expected := strings.Join([]string{
+		"NotFound: The cat Lucie is missing",
+		"  MessageKey: cat.missing",
+		"  MessageArgs: name=Lucie",
+		"  ObjKind: Cat",
+		"  Obj: Lucie",
+		"  Cause: RuntimeFault: The shelter is closed",
+		"    Cause: Timeout",
+		"      Operation: Open",
+		"  Causes[0]: InvalidArgument",
+		"    Argument: name",
+		"  Causes[1]: <nil>",
+	}, "\n")
+	if s := fmt.Sprintf("%+v", f); s != expected {
+		t.Errorf("Unexpected cause chain\n%s\nexpected\n%s", s, expected)
+	}
+}
+
+func TestUnmarshalNarrowingError(t *testing.T) {
+	_, err := UnmarshalNotFound([]byte(`{"Kind":"Fault","Message":"boom"}`))
+	if err == nil || err.Error() != "cannot unmarshal NotFound from Fault: boom" {
+		t.Error("Unexpected error", err)
+	}
+	if errors.Unwrap(err) != nil {
+		t.Error("Expected the error not to wrap the fault", err)
+	}
+}
+
+func TestFormatMultilineMessage(t *testing.T) {
+	f := NewRuntimeFault(WithMessage("batch failed:\nitem 1\nitem 2"),
+		WithCause(NewNotFound("Cat", "Lucie\nMilo", WithMessage("a\nb"))))
+	expected := strings.Join([]string{
+		"RuntimeFault: batch failed:",
+		"    item 1",
+		"    item 2",
+		"  Cause: NotFound: a",
+		"      b",
+		"    ObjKind: Cat",
+		"    Obj: Lucie",
+		"      Milo",
+	}, "\n")
+	if s := fmt.Sprintf("%+v", f); s != expected {
+		t.Errorf("Unexpected multi-line message\n%s\nexpected\n%s", s, expected)
+	}
+}
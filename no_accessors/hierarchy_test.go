@@ -0,0 +1,45 @@
+package no_accessors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	faults := []BaseFault{fault, runtimeFault, notFound}
+	for _, f := range faults {
+		if KindOf(f) != f.GetKind() {
+			t.Error("Kind mismatch", KindOf(f), f.GetKind())
+		}
+	}
+	if KindOf(notFound) != "NotFound" {
+		t.Error("Unexpected kind:", KindOf(notFound))
+	}
+	if KindOf(nil) != "" {
+		t.Error("Expected no kind for nil")
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	if parent, ok := Parent("NotFound"); !ok || parent != "RuntimeFault" {
+		t.Error("Unexpected parent of NotFound:", parent)
+	}
+	if _, ok := Parent("Fault"); ok {
+		t.Error("Expected Fault to be the root")
+	}
+	ancestors := Ancestors("NotFound")
+	if !reflect.DeepEqual(ancestors, []string{"RuntimeFault", "Fault"}) {
+		t.Error("Unexpected ancestors:", ancestors)
+	}
+	descendants := Descendants("Fault")
+	if !reflect.DeepEqual(descendants, []string{"RuntimeFault", "AlreadyExists", "Conflict",
+		"InvalidArgument", "NotFound", "PermissionDenied", "ResourceExhausted", "Timeout"}) {
+		t.Error("Unexpected descendants:", descendants)
+	}
+	if !IsA("NotFound", "Fault") || !IsA("RuntimeFault", "RuntimeFault") {
+		t.Error("Expected IsA to follow the hierarchy")
+	}
+	if IsA("Fault", "RuntimeFault") || IsA("Missing", "Fault") {
+		t.Error("Unexpected IsA relation")
+	}
+}
@@ -0,0 +1,149 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// BaseInvalidArgument represents error when an argument of the request is not
+// valid
+type BaseInvalidArgument interface {
+	BaseRuntimeFault
+	GetInvalidArgument() *InvalidArgument
+}
+
+// InvalidArgument contains the data about an argument that is not valid
+type InvalidArgument struct {
+	RuntimeFault
+	Argument string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "InvalidArgument",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &InvalidArgument{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 400,
+			ExitCode:   64, // EX_USAGE
+		},
+	})
+}
+
+var _ BaseInvalidArgument = &InvalidArgument{}
+var _ BaseRuntimeFault = &InvalidArgument{}
+var _ BaseFault = &InvalidArgument{}
+var _ json.Marshaler = &InvalidArgument{}
+var _ json.Unmarshaler = &InvalidArgument{}
+var _ fmt.Formatter = &InvalidArgument{}
+
+// NewInvalidArgument creates an InvalidArgument for the named argument.
+// The options set the members inherited from Fault like the message.
+func NewInvalidArgument(argument string, opts ...Option) *InvalidArgument {
+	x := &InvalidArgument{Argument: argument}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (ia *InvalidArgument) GetKind() string {
+	return "InvalidArgument"
+}
+
+// Error formats the fault with its kind, message, the argument that is not
+// valid and the cause
+func (ia *InvalidArgument) Error() string {
+	return formatError("InvalidArgument", ia.Message, ia.Argument, ia.Cause, ia.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (ia *InvalidArgument) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, ia)
+}
+
+func (ia *InvalidArgument) GetInvalidArgument() *InvalidArgument {
+	return ia
+}
+
+// MarshalJSON writes InvalidArgument as JSON
+func (ia *InvalidArgument) MarshalJSON() ([]byte, error) {
+	return marshal(ia)
+}
+
+// encode writes the Kind first and then the members directly
+func (ia *InvalidArgument) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "InvalidArgument")
+	if err != nil {
+		return err
+	}
+	err = ia.RuntimeFault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Argument")
+	e.String(ia.Argument)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (ia *InvalidArgument) UnmarshalJSON(in []byte) error {
+	return unmarshal(ia, in, false)
+}
+
+func (ia *InvalidArgument) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		Argument      string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "InvalidArgument", strict)
+	if err != nil {
+		return err
+	}
+	var cause BaseFault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	ia.Message = pxy.Message
+	ia.MessageKey = pxy.MessageKey
+	ia.MessageArgs = pxy.MessageArgs
+	ia.Cause = cause
+	ia.Causes = causes
+	ia.Argument = pxy.Argument
+	return nil
+}
+
+// UnmarshalInvalidArgument reads InvalidArgument or its subclasses from JSON
+// bytes
+func UnmarshalInvalidArgument(in []byte) (BaseInvalidArgument, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if invalidArgument, ok := fault.(BaseInvalidArgument); ok {
+		return invalidArgument, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal InvalidArgument from %v", fault)
+}
@@ -0,0 +1,114 @@
+package no_accessors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+)
+
+// Rule converts an error into a fault. It returns nil if the error is not one
+// it handles. Rules look through wrapped errors with errors.As and errors.Is.
+type Rule func(err error) BaseFault
+
+// Mapper converts errors at an API boundary into catalog faults. It tries its
+// rules in order and converts the errors no rule handles with FromError.
+// A Mapper is not modified after NewMapper so it can be shared.
+type Mapper struct {
+	rules []Rule
+}
+
+// StandardRules map errors of the standard library to catalog faults:
+//
+//	*os.PathError with os.ErrNotExist     NotFound with ObjKind "File"
+//	*os.PathError with os.ErrExist        AlreadyExists with ObjKind "File"
+//	*os.PathError with os.ErrPermission   PermissionDenied
+//	*json.SyntaxError                     InvalidArgument
+//	*json.UnmarshalTypeError              InvalidArgument
+//	context.DeadlineExceeded and errors
+//	with Timeout() true like *net.OpError Timeout
+//	other *net.OpError                    RuntimeFault
+var StandardRules = []Rule{pathRule, jsonRule, timeoutRule, netRule}
+
+var standardMapper = NewMapper()
+
+// NewMapper returns a mapper that tries rules before StandardRules
+func NewMapper(rules ...Rule) *Mapper {
+	all := make([]Rule, 0, len(rules)+len(StandardRules))
+	all = append(all, rules...)
+	all = append(all, StandardRules...)
+	return &Mapper{rules: all}
+}
+
+// Map converts err into a fault. Faults are returned as they are. The first
+// rule that handles err decides the fault. Other errors are converted by
+// FromError.
+func (m *Mapper) Map(err error) BaseFault {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(BaseFault); !ok {
+		for _, rule := range m.rules {
+			if f := rule(err); f != nil {
+				return f
+			}
+		}
+	}
+	return FromError(err)
+}
+
+// MapError converts err into a fault with StandardRules
+func MapError(err error) BaseFault {
+	return standardMapper.Map(err)
+}
+
+func pathRule(err error) BaseFault {
+	var pathErr *os.PathError
+	if !errors.As(err, &pathErr) {
+		return nil
+	}
+	switch {
+	case errors.Is(pathErr, os.ErrNotExist):
+		return NewNotFound("File", pathErr.Path, WithMessage(err.Error()))
+	case errors.Is(pathErr, os.ErrExist):
+		return NewAlreadyExists("File", pathErr.Path, WithMessage(err.Error()))
+	case errors.Is(pathErr, os.ErrPermission):
+		return NewPermissionDenied(pathErr.Op, WithMessage(err.Error()))
+	}
+	return nil
+}
+
+func jsonRule(err error) BaseFault {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		argument := fmt.Sprintf("offset %d", syntaxErr.Offset)
+		return NewInvalidArgument(argument, WithMessage(err.Error()))
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return NewInvalidArgument(typeErr.Field, WithMessage(err.Error()))
+	}
+	return nil
+}
+
+func timeoutRule(err error) BaseFault {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Timeout() {
+		return NewTimeout(opErr.Op, WithMessage(err.Error()))
+	}
+	var timeout interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeout) && timeout.Timeout()) {
+		return NewTimeout("", WithMessage(err.Error()))
+	}
+	return nil
+}
+
+func netRule(err error) BaseFault {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return nil
+	}
+	return NewRuntimeFault(WithMessage(err.Error()))
+}
@@ -0,0 +1,92 @@
+package no_accessors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMapPathError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mapper")
+	if err != nil {
+		t.Error("Cannot create directory", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "missing")
+	_, err = os.Open(path)
+	f := MapError(fmt.Errorf("load config: %w", err))
+	if nf, ok := f.(*NotFound); !ok || nf.ObjKind != "File" || nf.Obj != path {
+		t.Error("Expected NotFound of the file", f)
+	} else if nf.Message != "load config: "+err.Error() {
+		t.Error("Unexpected message", nf.Message)
+	}
+	f = MapError(os.Mkdir(dir, 0700))
+	if ae, ok := f.(*AlreadyExists); !ok || ae.ObjKind != "File" || ae.Obj != dir {
+		t.Error("Expected AlreadyExists of the directory", f)
+	}
+	f = MapError(&os.PathError{Op: "open", Path: path, Err: os.ErrPermission})
+	if pd, ok := f.(*PermissionDenied); !ok || pd.Privilege != "open" {
+		t.Error("Expected PermissionDenied", f)
+	}
+}
+
+func TestMapTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	if f := MapError(ctx.Err()); f.GetKind() != "Timeout" {
+		t.Error("Expected Timeout for the deadline", f)
+	}
+	f := MapError(&net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded})
+	if to, ok := f.(*Timeout); !ok || to.Operation != "read" {
+		t.Error("Expected Timeout of the read", f)
+	}
+	f = MapError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+	if _, ok := f.(*RuntimeFault); !ok {
+		t.Error("Expected RuntimeFault for the network error", f)
+	}
+}
+
+func TestMapJSONError(t *testing.T) {
+	err := json.Unmarshal([]byte(`{"a":`), &struct{}{})
+	if ia, ok := MapError(err).(*InvalidArgument); !ok || ia.Argument != "offset 5" {
+		t.Error("Expected InvalidArgument for the syntax error", err)
+	}
+	err = json.Unmarshal([]byte(`{"Size":"big"}`), &struct{ Size int }{})
+	if ia, ok := MapError(err).(*InvalidArgument); !ok || ia.Argument != "Size" {
+		t.Error("Expected InvalidArgument for the type error", err)
+	}
+}
+
+func TestMapperRules(t *testing.T) {
+	if MapError(nil) != nil || MapError(notFound) != notFound {
+		t.Error("Expected nil and faults to be kept")
+	}
+	if f := MapError(io.EOF); f.GetKind() != "Fault" {
+		t.Error("Expected other errors to be converted by FromError", f)
+	}
+	m := NewMapper(func(err error) BaseFault {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, os.ErrNotExist) {
+			return NewInvalidArgument("body", WithMessage(err.Error()))
+		}
+		return nil
+	})
+	if f := m.Map(io.ErrUnexpectedEOF); f.GetKind() != "InvalidArgument" {
+		t.Error("Expected the rule of the caller", f)
+	}
+	if f := m.Map(&os.PathError{Op: "open", Path: "p", Err: os.ErrNotExist}); f.GetKind() != "InvalidArgument" {
+		t.Error("Expected the rule of the caller before the standard rules", f)
+	}
+	if f := m.Map(context.DeadlineExceeded); f.GetKind() != "Timeout" {
+		t.Error("Expected the standard rules", f)
+	}
+}
@@ -3,7 +3,9 @@ package no_accessors
 import (
 	"encoding/json"
 	"fmt"
-	"reflect"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // BaseNotFound represents error when object is not found
@@ -21,7 +23,17 @@ type NotFound struct {
 }
 
 func init() {
-	t["NotFound"] = reflect.TypeOf((*NotFound)(nil)).Elem()
+	registry.Register(polymorphic.Type{
+		Kind:   "NotFound",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &NotFound{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 404,
+			ExitCode:   66, // EX_NOINPUT
+		},
+	})
 }
 
 var _ BaseNotFound = &NotFound{}
@@ -29,6 +41,33 @@ var _ BaseRuntimeFault = &NotFound{}
 var _ BaseFault = &NotFound{}
 var _ json.Marshaler = &NotFound{}
 var _ json.Unmarshaler = &NotFound{}
+var _ fmt.Formatter = &NotFound{}
+
+// NewNotFound creates a NotFound for the object of objKind with id obj.
+// The options set the members inherited from Fault like the message.
+func NewNotFound(objKind, obj string, opts ...Option) *NotFound {
+	x := &NotFound{ObjKind: objKind, Obj: obj}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (f *NotFound) GetKind() string {
+	return "NotFound"
+}
+
+// Error formats the fault with its kind, message, the object that is not
+// found and the cause
+func (f *NotFound) Error() string {
+	details := strings.TrimSpace(f.ObjKind + " " + f.Obj)
+	return formatError("NotFound", f.Message, details, f.Cause, f.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (f *NotFound) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, f)
+}
 
 func (f *NotFound) GetNotFound() *NotFound {
 	return f
@@ -36,37 +75,68 @@ func (f *NotFound) GetNotFound() *NotFound {
 
 // MarshalJSON writes a NotFoundObject as JSON
 func (nfo *NotFound) MarshalJSON() ([]byte, error) {
-	type marshalable NotFound
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "NotFound",
-		marshalable: marshalable(*nfo),
-	})
+	return marshal(nfo)
+}
+
+// encode writes the Kind first and then the members directly
+func (nfo *NotFound) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "NotFound")
+	if err != nil {
+		return err
+	}
+	err = nfo.RuntimeFault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(nfo.ObjKind)
+	e.Name("Obj")
+	e.String(nfo.Obj)
+	e.EndObject()
+	return nil
 }
 
 // UnmarshalJSON reads a fault from JSON
 func (nfo *NotFound) UnmarshalJSON(in []byte) error {
+	return unmarshal(nfo, in, false)
+}
+
+func (nfo *NotFound) unmarshalValue(v *polymorphic.Value, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   json.RawMessage
-		ObjKind string
-		Obj     string
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		ObjKind       string
+		Obj           string
 	}{}
-	err := json.Unmarshal(in, pxy)
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "NotFound", strict)
 	if err != nil {
 		return err
 	}
 	var cause BaseFault
 	if pxy.Cause != nil {
-		cause, err = UnmarshalFault(pxy.Cause)
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
 		if err != nil {
 			return err
 		}
 	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
 	nfo.Message = pxy.Message
+	nfo.MessageKey = pxy.MessageKey
+	nfo.MessageArgs = pxy.MessageArgs
 	nfo.Cause = cause
+	nfo.Causes = causes
 	nfo.Obj = pxy.Obj
 	nfo.ObjKind = pxy.ObjKind
 	return nil
@@ -81,5 +151,5 @@ func UnmarshalNotFound(in []byte) (BaseNotFound, error) {
 	if notFound, ok := fault.(BaseNotFound); ok {
 		return notFound, nil
 	}
-	return nil, fmt.Errorf("cannot unmarshal NotFound %v", fault)
+	return nil, fmt.Errorf("cannot unmarshal NotFound from %v", fault)
 }
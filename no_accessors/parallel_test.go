@@ -0,0 +1,93 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// faultsArray returns a JSON array of n faults cycling through the kinds
+func faultsArray(tb testing.TB, n int) []byte {
+	faults := make([]BaseFault, n)
+	for i := range faults {
+		faults[i] = []BaseFault{fault, runtimeFault, notFound}[i%3]
+	}
+	in, err := json.Marshal(faults)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return in
+}
+
+func TestUnmarshalFaultsParallel(t *testing.T) {
+	faults, err := UnmarshalFaultsParallel(faultsArray(t, 300), 4)
+	if err != nil {
+		t.Error("Cannot deserialize faults", err)
+		return
+	}
+	if len(faults) != 300 {
+		t.Error("Unexpected number of faults", len(faults))
+		return
+	}
+	for i := 0; i < len(faults); i += 3 {
+		validateFault(faults[i], t)
+		validateRuntimeFault(faults[i+1], t)
+		validateNotFound(faults[i+2], t)
+	}
+
+	faults, err = UnmarshalFaultsParallel([]byte("null"), 0)
+	if err != nil || faults != nil {
+		t.Error("Expected no faults for null", faults, err)
+	}
+}
+
+func TestUnmarshalFaultsParallelError(t *testing.T) {
+	in := string(faultsArray(t, 10))
+	in = in[:len(in)-1] + `,{"Kind":"Fault","Message":1},{"Kind":"Fault","Message":2}]`
+	_, err := UnmarshalFaultsParallel([]byte(in), 4)
+	var ee *polymorphic.ElementError
+	if !errors.As(err, &ee) || ee.Index != 10 {
+		t.Error("Expected the error of element 10", err)
+	}
+	t.Log("Error", err)
+	_, err = UnmarshalFaultsParallel([]byte(strings.TrimSuffix(in, "]")), 4)
+	if err == nil {
+		t.Error("Expected to fail on a truncated array")
+	}
+}
+
+// BenchmarkUnmarshalFaults compares decoding the elements one after another
+// with the parallel decoder over growing arrays. The small arrays show the
+// cost of the worker pool. Run it with -cpu 1,2,4,8 to find the array size at
+// which the parallel decoder overtakes on the host.
+func BenchmarkUnmarshalFaults(b *testing.B) {
+	for _, n := range []int{1, 4, 16, 64, 256, 4096} {
+		in := faultsArray(b, n)
+		container := []byte(`{"Faults":` + string(in) + "}")
+		b.Run(fmt.Sprintf("sequential/%d", n), func(b *testing.B) {
+			b.SetBytes(int64(len(in)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				c := ArrayContainer{}
+				err := json.Unmarshal(container, &c)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("parallel/%d", n), func(b *testing.B) {
+			b.SetBytes(int64(len(in)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := UnmarshalFaultsParallel(in, 0)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
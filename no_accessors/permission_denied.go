@@ -0,0 +1,148 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// BasePermissionDenied represents error when the caller is not allowed to
+// perform the operation
+type BasePermissionDenied interface {
+	BaseRuntimeFault
+	GetPermissionDenied() *PermissionDenied
+}
+
+// PermissionDenied contains the data about a denied operation
+type PermissionDenied struct {
+	RuntimeFault
+	Privilege string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "PermissionDenied",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &PermissionDenied{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			HTTPStatus: 403,
+			ExitCode:   77, // EX_NOPERM
+		},
+	})
+}
+
+var _ BasePermissionDenied = &PermissionDenied{}
+var _ BaseRuntimeFault = &PermissionDenied{}
+var _ BaseFault = &PermissionDenied{}
+var _ json.Marshaler = &PermissionDenied{}
+var _ json.Unmarshaler = &PermissionDenied{}
+var _ fmt.Formatter = &PermissionDenied{}
+
+// NewPermissionDenied creates a PermissionDenied for the missing privilege.
+// The options set the members inherited from Fault like the message.
+func NewPermissionDenied(privilege string, opts ...Option) *PermissionDenied {
+	x := &PermissionDenied{Privilege: privilege}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (pd *PermissionDenied) GetKind() string {
+	return "PermissionDenied"
+}
+
+// Error formats the fault with its kind, message, the missing privilege
+// and the cause
+func (pd *PermissionDenied) Error() string {
+	return formatError("PermissionDenied", pd.Message, pd.Privilege, pd.Cause, pd.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (pd *PermissionDenied) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, pd)
+}
+
+func (pd *PermissionDenied) GetPermissionDenied() *PermissionDenied {
+	return pd
+}
+
+// MarshalJSON writes PermissionDenied as JSON
+func (pd *PermissionDenied) MarshalJSON() ([]byte, error) {
+	return marshal(pd)
+}
+
+// encode writes the Kind first and then the members directly
+func (pd *PermissionDenied) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "PermissionDenied")
+	if err != nil {
+		return err
+	}
+	err = pd.RuntimeFault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Privilege")
+	e.String(pd.Privilege)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (pd *PermissionDenied) UnmarshalJSON(in []byte) error {
+	return unmarshal(pd, in, false)
+}
+
+func (pd *PermissionDenied) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		Privilege     string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "PermissionDenied", strict)
+	if err != nil {
+		return err
+	}
+	var cause BaseFault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	pd.Message = pxy.Message
+	pd.MessageKey = pxy.MessageKey
+	pd.MessageArgs = pxy.MessageArgs
+	pd.Cause = cause
+	pd.Causes = causes
+	pd.Privilege = pxy.Privilege
+	return nil
+}
+
+// UnmarshalPermissionDenied reads PermissionDenied or its subclasses from JSON
+// bytes
+func UnmarshalPermissionDenied(in []byte) (BasePermissionDenied, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if permissionDenied, ok := fault.(BasePermissionDenied); ok {
+		return permissionDenied, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal PermissionDenied from %v", fault)
+}
@@ -0,0 +1,146 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// BaseResourceExhausted represents error when a quota or the capacity of a
+// resource is exhausted
+type BaseResourceExhausted interface {
+	BaseRuntimeFault
+	GetResourceExhausted() *ResourceExhausted
+}
+
+// ResourceExhausted contains the data about an exhausted resource
+type ResourceExhausted struct {
+	RuntimeFault
+	Resource string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "ResourceExhausted",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &ResourceExhausted{} },
+		Classification: polymorphic.Classification{
+			HTTPStatus: 429,
+		},
+	})
+}
+
+var _ BaseResourceExhausted = &ResourceExhausted{}
+var _ BaseRuntimeFault = &ResourceExhausted{}
+var _ BaseFault = &ResourceExhausted{}
+var _ json.Marshaler = &ResourceExhausted{}
+var _ json.Unmarshaler = &ResourceExhausted{}
+var _ fmt.Formatter = &ResourceExhausted{}
+
+// NewResourceExhausted creates a ResourceExhausted for the exhausted resource.
+// The options set the members inherited from Fault like the message.
+func NewResourceExhausted(resource string, opts ...Option) *ResourceExhausted {
+	x := &ResourceExhausted{Resource: resource}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (rex *ResourceExhausted) GetKind() string {
+	return "ResourceExhausted"
+}
+
+// Error formats the fault with its kind, message, the exhausted resource
+// and the cause
+func (rex *ResourceExhausted) Error() string {
+	return formatError("ResourceExhausted", rex.Message, rex.Resource, rex.Cause, rex.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (rex *ResourceExhausted) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, rex)
+}
+
+func (rex *ResourceExhausted) GetResourceExhausted() *ResourceExhausted {
+	return rex
+}
+
+// MarshalJSON writes ResourceExhausted as JSON
+func (rex *ResourceExhausted) MarshalJSON() ([]byte, error) {
+	return marshal(rex)
+}
+
+// encode writes the Kind first and then the members directly
+func (rex *ResourceExhausted) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "ResourceExhausted")
+	if err != nil {
+		return err
+	}
+	err = rex.RuntimeFault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Resource")
+	e.String(rex.Resource)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (rex *ResourceExhausted) UnmarshalJSON(in []byte) error {
+	return unmarshal(rex, in, false)
+}
+
+func (rex *ResourceExhausted) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		Resource      string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "ResourceExhausted", strict)
+	if err != nil {
+		return err
+	}
+	var cause BaseFault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	rex.Message = pxy.Message
+	rex.MessageKey = pxy.MessageKey
+	rex.MessageArgs = pxy.MessageArgs
+	rex.Cause = cause
+	rex.Causes = causes
+	rex.Resource = pxy.Resource
+	return nil
+}
+
+// UnmarshalResourceExhausted reads ResourceExhausted or its subclasses from
+// JSON bytes
+func UnmarshalResourceExhausted(in []byte) (BaseResourceExhausted, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if resourceExhausted, ok := fault.(BaseResourceExhausted); ok {
+		return resourceExhausted, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal ResourceExhausted from %v", fault)
+}
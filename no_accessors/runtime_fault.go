@@ -3,7 +3,8 @@ package no_accessors
 import (
 	"encoding/json"
 	"fmt"
-	"reflect"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // BaseRuntimeFault represents all runtime faults that can be thrown
@@ -19,13 +20,47 @@ type RuntimeFault struct {
 }
 
 func init() {
-	t["RuntimeFault"] = reflect.TypeOf((*RuntimeFault)(nil)).Elem()
+	registry.Register(polymorphic.Type{
+		Kind:   "RuntimeFault",
+		Parent: "Fault",
+		New:    func() interface{} { return &RuntimeFault{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.Retryable,
+			HTTPStatus: 503,
+			ExitCode:   75, // EX_TEMPFAIL of sysexits.h
+		},
+	})
 }
 
 var _ BaseFault = &RuntimeFault{}
 var _ BaseRuntimeFault = &RuntimeFault{}
 var _ json.Marshaler = &RuntimeFault{}
 var _ json.Unmarshaler = &RuntimeFault{}
+var _ fmt.Formatter = &RuntimeFault{}
+
+// NewRuntimeFault creates a RuntimeFault.
+// The options set the members inherited from Fault like the message.
+func NewRuntimeFault(opts ...Option) *RuntimeFault {
+	x := &RuntimeFault{}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (f *RuntimeFault) GetKind() string {
+	return "RuntimeFault"
+}
+
+// Error formats the fault with its kind, message and cause
+func (f *RuntimeFault) Error() string {
+	return formatError("RuntimeFault", f.Message, "", f.Cause, f.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (f *RuntimeFault) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, f)
+}
 
 func (f *RuntimeFault) GetRuntimeFault() *RuntimeFault {
 	return f
@@ -33,35 +68,62 @@ func (f *RuntimeFault) GetRuntimeFault() *RuntimeFault {
 
 // MarshalJSON writes a RuntimeFaultObject as JSON
 func (rf *RuntimeFault) MarshalJSON() ([]byte, error) {
-	type marshalable RuntimeFault
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "RuntimeFault",
-		marshalable: marshalable(*rf),
-	})
+	return marshal(rf)
+}
+
+// encode writes the Kind first and then the members directly
+func (rf *RuntimeFault) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "RuntimeFault")
+	if err != nil {
+		return err
+	}
+	err = rf.Fault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.EndObject()
+	return nil
 }
 
 // UnmarshalJSON reads a fault from JSON
 func (rf *RuntimeFault) UnmarshalJSON(in []byte) error {
+	return unmarshal(rf, in, false)
+}
+
+func (rf *RuntimeFault) unmarshalValue(v *polymorphic.Value, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   json.RawMessage
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
 	}{}
-	err := json.Unmarshal(in, pxy)
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "RuntimeFault", strict)
 	if err != nil {
 		return err
 	}
 	var cause BaseFault
 	if pxy.Cause != nil {
-		cause, err = UnmarshalFault(pxy.Cause)
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
 		if err != nil {
 			return err
 		}
 	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
 	rf.Message = pxy.Message
+	rf.MessageKey = pxy.MessageKey
+	rf.MessageArgs = pxy.MessageArgs
 	rf.Cause = cause
+	rf.Causes = causes
 	return nil
 }
 
@@ -74,5 +136,5 @@ func UnmarshalRuntimeFault(in []byte) (BaseRuntimeFault, error) {
 	if runtimeFault, ok := fault.(BaseRuntimeFault); ok {
 		return runtimeFault, nil
 	}
-	return nil, fmt.Errorf("cannot unmarshal RuntimeFault %v", fault)
+	return nil, fmt.Errorf("cannot unmarshal RuntimeFault from %v", fault)
 }
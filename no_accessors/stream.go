@@ -0,0 +1,64 @@
+package no_accessors
+
+import (
+	"context"
+	"io"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// FaultStream decodes faults one at a time from a JSON array or an NDJSON
+// stream. It holds only the current fault in memory so it reads exports that
+// do not fit in memory.
+//
+//	s := NewFaultStream(r)
+//	for s.Next(ctx) {
+//		handle(s.Fault())
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type FaultStream struct {
+	stream *polymorphic.Stream
+	fault  BaseFault
+	err    error
+}
+
+// NewFaultStream creates a stream of the faults read from r
+func NewFaultStream(r io.Reader) *FaultStream {
+	return &FaultStream{stream: polymorphic.NewStream(r)}
+}
+
+// Next decodes the next fault. It returns false at the end of the input, on
+// error and when ctx is done. Decoding errors are reported by Err as
+// *polymorphic.ElementError with the index of the element.
+func (s *FaultStream) Next(ctx context.Context) bool {
+	if s.err != nil || !s.stream.Next(ctx) {
+		return false
+	}
+	s.fault, s.err = UnmarshalFault(s.stream.Raw())
+	if s.err != nil {
+		s.fault = nil
+		s.err = &polymorphic.ElementError{Index: s.stream.Index(), Err: s.err}
+		return false
+	}
+	return true
+}
+
+// Fault returns the current fault. It is nil for null elements.
+func (s *FaultStream) Fault() BaseFault {
+	return s.fault
+}
+
+// Index returns the position of the current fault starting from 0
+func (s *FaultStream) Index() int {
+	return s.stream.Index()
+}
+
+// Err returns the error that stopped the stream or nil at the end of input
+func (s *FaultStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.stream.Err()
+}
@@ -0,0 +1,51 @@
+package no_accessors
+
+import (
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// unmarshaler is implemented by all fault structs. They read the JSON indexed
+// by polymorphic.Parse so nested causes are not scanned again and the strict
+// flag reaches the nested Cause fields.
+type unmarshaler interface {
+	unmarshalValue(v *polymorphic.Value, strict bool) error
+}
+
+var _ unmarshaler = &Fault{}
+var _ unmarshaler = &RuntimeFault{}
+var _ unmarshaler = &NotFound{}
+var _ unmarshaler = &InvalidArgument{}
+var _ unmarshaler = &AlreadyExists{}
+var _ unmarshaler = &PermissionDenied{}
+var _ unmarshaler = &Timeout{}
+var _ unmarshaler = &Conflict{}
+var _ unmarshaler = &ResourceExhausted{}
+
+// UnmarshalFaultStrict reads a fault from JSON like UnmarshalFault. It fails
+// on members that are not known to the kind instead of ignoring them. Nested
+// causes are read in strict mode too.
+func UnmarshalFaultStrict(in []byte) (BaseFault, error) {
+	return unmarshalFault(in, true)
+}
+
+// UnmarshalStrict reads JSON into the fault struct v. It fails on unknown
+// members and when the Kind in the payload is neither the type of v nor one of
+// its descendants.
+func UnmarshalStrict(in []byte, v BaseFault) error {
+	u, ok := v.(unmarshaler)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal into %T", v)
+	}
+	return unmarshal(u, in, true)
+}
+
+// unmarshal indexes the JSON and reads it into the fault struct
+func unmarshal(u unmarshaler, in []byte, strict bool) error {
+	v, err := polymorphic.Parse(in)
+	if err != nil {
+		return err
+	}
+	return u.unmarshalValue(v, strict)
+}
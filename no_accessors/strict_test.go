@@ -0,0 +1,99 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStrictFault(t *testing.T) {
+	b, err := json.Marshal(notFound)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+
+	t.Log("JSON Bytes", string(b))
+
+	fault, err := UnmarshalFaultStrict(b)
+	if err != nil {
+		t.Error("Cannot deserialize fault in strict mode", err)
+		return
+	}
+	validateNotFound(fault, t)
+}
+
+func TestStrictKindMismatch(t *testing.T) {
+	b := []byte(`{"Kind":"Fault","Message":"test message"}`)
+
+	nf := NotFound{}
+	err := json.Unmarshal(b, &nf)
+	if err != nil {
+		t.Error("Expected lenient unmarshal to ignore the kind", err)
+	}
+	err = UnmarshalStrict(b, &nf)
+	if err == nil {
+		t.Error("Expected to fail reading Fault into NotFoundStruct")
+	}
+	t.Log("Strict error", err)
+}
+
+func TestStrictDescendantKind(t *testing.T) {
+	b, err := json.Marshal(runtimeFault)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+
+	f := Fault{}
+	err = UnmarshalStrict(b, &f)
+	if err != nil {
+		t.Error("Expected to read RuntimeFault into FaultStruct", err)
+		return
+	}
+	validateFault(&f, t)
+}
+
+func TestStrictUnknownField(t *testing.T) {
+	payloads := []string{
+		`{"Kind":"NotFound","Mesage":"test message"}`,
+		`{"Kind":"RuntimeFault","Cause":{"Kind":"Fault","Obj":"vm-42"}}`,
+	}
+	for _, p := range payloads {
+		_, err := UnmarshalFault([]byte(p))
+		if err != nil {
+			t.Error("Expected lenient unmarshal to succeed", p, err)
+		}
+		_, err = UnmarshalFaultStrict([]byte(p))
+		if err == nil {
+			t.Error("Expected strict unmarshal to fail", p)
+		}
+		t.Log("Strict error", err)
+	}
+}
+
+func TestDuplicateKind(t *testing.T) {
+	in := []byte(`{"Kind":"NotFound","Kind":"Fault","Message":"m"}`)
+	lenient, err := UnmarshalFault(in)
+	if err != nil || lenient.GetKind() != "Fault" {
+		t.Error("Expected the last kind to select the type", lenient, err)
+	}
+	strict, err := UnmarshalFaultStrict(in)
+	if err != nil || strict.GetKind() != "Fault" {
+		t.Error("Expected strict mode to agree with lenient mode", strict, err)
+	}
+}
+
+func TestStrictTrailingData(t *testing.T) {
+	payloads := []string{
+		`{"Kind":"NotFound"}}`,
+		`{"Kind":"NotFound"}]`,
+		`{"Kind":"NotFound"} {}`,
+	}
+	for _, p := range payloads {
+		_, err := UnmarshalFaultStrict([]byte(p))
+		if err == nil {
+			t.Error("Expected strict unmarshal to reject trailing data", p)
+		}
+		t.Log("Strict error", err)
+	}
+}
@@ -0,0 +1,144 @@
+package no_accessors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// BaseTimeout represents error when an operation does not complete in time
+type BaseTimeout interface {
+	BaseRuntimeFault
+	GetTimeout() *Timeout
+}
+
+// Timeout contains the data about an operation that timed out
+type Timeout struct {
+	RuntimeFault
+	Operation string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "Timeout",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &Timeout{} },
+		Classification: polymorphic.Classification{
+			HTTPStatus: 504,
+		},
+	})
+}
+
+var _ BaseTimeout = &Timeout{}
+var _ BaseRuntimeFault = &Timeout{}
+var _ BaseFault = &Timeout{}
+var _ json.Marshaler = &Timeout{}
+var _ json.Unmarshaler = &Timeout{}
+var _ fmt.Formatter = &Timeout{}
+
+// NewTimeout creates a Timeout of the operation.
+// The options set the members inherited from Fault like the message.
+func NewTimeout(operation string, opts ...Option) *Timeout {
+	x := &Timeout{Operation: operation}
+	apply(x.GetFault(), opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (to *Timeout) GetKind() string {
+	return "Timeout"
+}
+
+// Error formats the fault with its kind, message, the operation that timed out
+// and the cause
+func (to *Timeout) Error() string {
+	return formatError("Timeout", to.Message, to.Operation, to.Cause, to.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (to *Timeout) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, to)
+}
+
+func (to *Timeout) GetTimeout() *Timeout {
+	return to
+}
+
+// MarshalJSON writes Timeout as JSON
+func (to *Timeout) MarshalJSON() ([]byte, error) {
+	return marshal(to)
+}
+
+// encode writes the Kind first and then the members directly
+func (to *Timeout) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "Timeout")
+	if err != nil {
+		return err
+	}
+	err = to.RuntimeFault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Operation")
+	e.String(to.Operation)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (to *Timeout) UnmarshalJSON(in []byte) error {
+	return unmarshal(to, in, false)
+}
+
+func (to *Timeout) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		Operation     string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "Timeout", strict)
+	if err != nil {
+		return err
+	}
+	var cause BaseFault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	to.Message = pxy.Message
+	to.MessageKey = pxy.MessageKey
+	to.MessageArgs = pxy.MessageArgs
+	to.Cause = cause
+	to.Causes = causes
+	to.Operation = pxy.Operation
+	return nil
+}
+
+// UnmarshalTimeout reads Timeout or its subclasses from JSON bytes
+func UnmarshalTimeout(in []byte) (BaseTimeout, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if timeout, ok := fault.(BaseTimeout); ok {
+		return timeout, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal Timeout from %v", fault)
+}
@@ -0,0 +1,72 @@
+package polymorphic
+
+// Classification describes how callers handle a failure of a kind. Members
+// left at their zero value are inherited from the parent kind so a kind sets
+// only what differs from its ancestors.
+type Classification struct {
+	// Retry tells whether the operation that failed may succeed when retried
+	Retry Retryability
+	// Severity ranks how serious the failure is
+	Severity Severity
+	// HTTPStatus is the status code of responses that carry the failure
+	HTTPStatus int
+	// ExitCode is the exit status of commands that fail with the failure
+	ExitCode int
+}
+
+// Retryability tells whether a failed operation may be retried
+type Retryability int
+
+const (
+	// RetryUnspecified inherits the retryability of the parent kind
+	RetryUnspecified Retryability = iota
+	// Retryable failures are transient. The operation may succeed later.
+	Retryable
+	// NotRetryable failures repeat until the request or the state changes
+	NotRetryable
+)
+
+// Severity ranks how serious a failure is
+type Severity int
+
+const (
+	// SeverityUnspecified inherits the severity of the parent kind
+	SeverityUnspecified Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = []string{"unspecified", "info", "warning", "error", "critical"}
+
+// String returns the lower case name of the severity
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return "unknown"
+	}
+	return severityNames[s]
+}
+
+// IsRetryable reports whether the failure is transient
+func (c Classification) IsRetryable() bool {
+	return c.Retry == Retryable
+}
+
+// inherit fills the members that c leaves unset from the classification of
+// an ancestor
+func (c Classification) inherit(ancestor Classification) Classification {
+	if c.Retry == RetryUnspecified {
+		c.Retry = ancestor.Retry
+	}
+	if c.Severity == SeverityUnspecified {
+		c.Severity = ancestor.Severity
+	}
+	if c.HTTPStatus == 0 {
+		c.HTTPStatus = ancestor.HTTPStatus
+	}
+	if c.ExitCode == 0 {
+		c.ExitCode = ancestor.ExitCode
+	}
+	return c
+}
@@ -0,0 +1,98 @@
+package polymorphic
+
+import "fmt"
+
+// Discriminator names the properties that select the type of a JSON object.
+// Hierarchies discriminated by kind alone leave VersionProperty empty.
+// Kubernetes style resources use apiVersion and kind.
+type Discriminator struct {
+	VersionProperty string
+	KindProperty    string
+}
+
+// Scan reads the discriminator properties of the JSON object in and returns
+// them as registry key. The properties are matched case insensitively like
+// encoding/json matches struct fields. Missing properties are left empty. The
+// result is false if in is null.
+//
+// Scan walks the bytes once and does not decode the skipped values so the
+// object is parsed only once more when it is read into the type selected by
+// the key. If a property repeats the last occurrence selects the type, as
+// encoding/json and Value.Decode keep the last one too.
+func (d Discriminator) Scan(in []byte) (Key, bool, error) {
+	var key Key
+	s := &scanner{in: in}
+	ok, err := s.openObject()
+	if !ok || err != nil {
+		return key, false, err
+	}
+	for s.peek() != '}' {
+		name, escaped, err := s.readMember()
+		if err != nil {
+			return key, false, err
+		}
+		var value []byte
+		switch {
+		case equalFold(name, escaped, d.KindProperty):
+			value, err = s.readStringValue(d.KindProperty)
+			key.Kind = string(value)
+		case d.VersionProperty != "" && equalFold(name, escaped, d.VersionProperty):
+			value, err = s.readStringValue(d.VersionProperty)
+			key.Version = string(value)
+		default:
+			err = s.skipValue()
+		}
+		if err != nil {
+			return key, false, err
+		}
+		s.skipComma()
+	}
+	return key, true, nil
+}
+
+// ScanKind reads the kind property of the JSON object in like Scan and
+// ignores the version. The last occurrence of the property wins. The kind is
+// returned as a slice of in unless it has escape sequences so the dispatch
+// does not allocate. The result is false if in is null.
+func (d Discriminator) ScanKind(in []byte) ([]byte, bool, error) {
+	s := &scanner{in: in}
+	ok, err := s.openObject()
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	var kind []byte
+	for s.peek() != '}' {
+		name, escaped, err := s.readMember()
+		if err != nil {
+			return nil, false, err
+		}
+		if equalFold(name, escaped, d.KindProperty) {
+			kind, err = s.readStringValue(d.KindProperty)
+		} else {
+			err = s.skipValue()
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		s.skipComma()
+	}
+	return kind, true, nil
+}
+
+// ValueKind reads the kind property of an object indexed by Parse like
+// ScanKind does from bytes. The result is false if the value is null.
+func (d Discriminator) ValueKind(v *Value) ([]byte, bool, error) {
+	if v.IsNull() {
+		return nil, false, nil
+	}
+	if !v.IsObject() {
+		return nil, false, fmt.Errorf("expected JSON object but found %q", v.Raw[0])
+	}
+	kind := v.Member(d.KindProperty)
+	if kind == nil {
+		return nil, true, nil
+	}
+	s := &scanner{in: kind.Raw}
+	value, err := s.readStringValue(d.KindProperty)
+	return value, err == nil, err
+}
@@ -0,0 +1,109 @@
+package polymorphic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiscriminatorScan(t *testing.T) {
+	d := Discriminator{VersionProperty: "apiVersion", KindProperty: "kind"}
+	key, ok, err := d.Scan([]byte(`{"spec":{"kind":"Inner"},"Kind":"Pod","apiVersion":"v1"}`))
+	if err != nil || !ok {
+		t.Error("Cannot scan discriminators", err)
+		return
+	}
+	if key.Version != "v1" || key.Kind != "Pod" {
+		t.Error("Unexpected key", key)
+	}
+	_, ok, err = d.Scan([]byte(`null`))
+	if err != nil || ok {
+		t.Error("Expected null to have no key", err)
+	}
+	payloads := []string{`[]`, `{"kind":1}`, `{"kind":`}
+	for _, p := range payloads {
+		_, _, err = d.Scan([]byte(p))
+		if err == nil {
+			t.Error("Expected to fail scanning", p)
+		}
+	}
+}
+
+func TestDiscriminatorScanSkipsValues(t *testing.T) {
+	d := Discriminator{KindProperty: "Kind"}
+	payloads := map[string]string{
+		` {"Message":"{\"Kind\":\"Inner\"}","Cause":{"Kind":"Fault","List":[1,{"a":[]}]},"kind":"NotFound"}`: "NotFound",
+		`{"Count":-1.5e+3,"Ok":true,"Cause":null,"Kind":"Fault","kind":"Other"}`:                             "Other",
+		`{"K\u0069nd":"F\u0061ult"}`:         "Fault",
+		`{"Kind":null}`:                      "",
+		`{"Message":"m"}`:                    "",
+		"{\n\t\"Kind\" : \"RuntimeFault\" }": "RuntimeFault",
+	}
+	for p, kind := range payloads {
+		key, ok, err := d.Scan([]byte(p))
+		if err != nil || !ok {
+			t.Error("Cannot scan", p, err)
+			continue
+		}
+		if key.Kind != kind {
+			t.Error("Unexpected kind", key.Kind, "in", p)
+		}
+	}
+	invalid := []string{``, `nu`, `"Kind"`, `{"Message":}`, `{"Message":"m"`, `{"Kind":"Fault`, `{Kind:"Fault"}`}
+	for _, p := range invalid {
+		_, _, err := d.Scan([]byte(p))
+		if err == nil {
+			t.Error("Expected to fail scanning", p)
+		}
+	}
+}
+
+var benchmarkPayload = []byte(`{"Kind":"NotFound","Message":"test message",` +
+	`"Cause":{"Kind":"RuntimeFault","Message":"inner message"},"ObjKind":"VirtualMachine","Obj":"vm-42"}`)
+
+func BenchmarkDiscriminatorScan(b *testing.B) {
+	d := Discriminator{KindProperty: "Kind"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, err := d.Scan(benchmarkPayload)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDiscriminatorUnmarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d := &struct {
+			Kind string
+		}{}
+		err := json.Unmarshal(benchmarkPayload, &d)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDiscriminatorScanKind(t *testing.T) {
+	d := Discriminator{KindProperty: "Kind"}
+	in := []byte(`{"Message":"m","Kind":"NotFound"}`)
+	kind, ok, err := d.ScanKind(in)
+	if err != nil || !ok || string(kind) != "NotFound" {
+		t.Error("Unexpected kind", string(kind), err)
+	}
+	if &kind[0] != &in[23] {
+		t.Error("Expected the kind to be a slice of the input")
+	}
+	kind, ok, err = d.ScanKind([]byte(`{"kind":"Not\u0046ound"}`))
+	if err != nil || !ok || string(kind) != "NotFound" {
+		t.Error("Unexpected escaped kind", string(kind), err)
+	}
+	kind, ok, err = d.ScanKind([]byte(`{"Message":"m"}`))
+	if err != nil || !ok || kind != nil {
+		t.Error("Expected no kind", string(kind), err)
+	}
+	_, ok, err = d.ScanKind([]byte(` null `))
+	if err != nil || ok {
+		t.Error("Expected null to have no kind", err)
+	}
+}
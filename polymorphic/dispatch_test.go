@@ -0,0 +1,76 @@
+package polymorphic
+
+import (
+	"fmt"
+	"testing"
+)
+
+// generatedKinds returns kinds shaped like a generated hierarchy where many
+// kinds share prefixes.
+func generatedKinds(n int) []string {
+	areas := []string{"", "Vm", "VmDisk", "Network", "NetworkPort", "Storage", "Not"}
+	kinds := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		kinds = append(kinds, fmt.Sprintf("%sFault%d", areas[i%len(areas)], i))
+	}
+	return kinds
+}
+
+func newGeneratedRegistry(kinds []string) *Registry {
+	r := NewRegistry()
+	for _, kind := range kinds {
+		r.Register(Type{
+			Kind: kind,
+			New:  func() interface{} { return &struct{ Message string }{} },
+		})
+	}
+	return r
+}
+
+func TestRegistryNewBytes(t *testing.T) {
+	r := newTestRegistry()
+	if _, ok := r.NewBytes([]byte("Cat")).(*cat); !ok {
+		t.Error("Expected to instantiate cat")
+	}
+	if r.NewBytes([]byte("Ca")) != nil || r.NewBytes(nil) != nil {
+		t.Error("Expected no type for unknown kinds")
+	}
+	r.Register(Type{Kind: "Lion", Parent: "Mammal", New: func() interface{} { return &cat{} }})
+	if r.NewBytes([]byte("Lion")) == nil {
+		t.Error("Expected kinds registered after a lookup")
+	}
+}
+
+func TestNewBytesAllocs(t *testing.T) {
+	kinds := generatedKinds(2500)
+	r := newGeneratedRegistry(kinds)
+	// New returns a preallocated value so only the lookup is measured
+	value := &cat{}
+	kind := "VirtualMachineNetworkAdapterConfigurationFault"
+	r.Register(Type{Kind: kind, New: func() interface{} { return value }})
+	wire := []byte(kind)
+	allocs := testing.AllocsPerRun(100, func() {
+		if r.NewBytes(wire) != value {
+			t.Error("Missing kind", string(wire))
+		}
+	})
+	if allocs != 0 {
+		t.Error("Expected NewBytes not to allocate", allocs)
+	}
+}
+
+func BenchmarkDispatch(b *testing.B) {
+	kinds := generatedKinds(2500)
+	r := newGeneratedRegistry(kinds)
+	wire := make([][]byte, len(kinds))
+	for i, kind := range kinds {
+		wire[i] = []byte(kind)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if r.NewBytes(wire[i%len(wire)]) == nil {
+			b.Fatal("Missing kind", string(wire[i%len(wire)]))
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package polymorphic
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// Encoder writes JSON objects member by member into a buffer that is reused
+// between values. The bindings write the discriminator first and then the
+// members of the kind directly instead of copying the value into a struct
+// that adds the discriminator.
+type Encoder struct {
+	buf   []byte
+	comma bool
+}
+
+// maxPooledBuffer keeps encoders of unusually large values out of the pool
+const maxPooledBuffer = 64 << 10
+
+var encoderPool = sync.Pool{
+	New: func() interface{} { return &Encoder{} },
+}
+
+// AcquireEncoder returns an empty encoder from a pool
+func AcquireEncoder() *Encoder {
+	return encoderPool.Get().(*Encoder)
+}
+
+// ReleaseEncoder returns the encoder to the pool. The bytes of the encoder
+// must not be used afterwards.
+func ReleaseEncoder(e *Encoder) {
+	if cap(e.buf) > maxPooledBuffer {
+		return
+	}
+	e.Reset()
+	encoderPool.Put(e)
+}
+
+// Reset empties the encoder keeping its buffer
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+	e.comma = false
+}
+
+// Bytes returns the JSON written so far. It is valid until the encoder is
+// reset or released.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// WriteTo writes the JSON to w
+func (e *Encoder) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(e.buf)
+	return int64(n), err
+}
+
+// BeginObject starts an object
+func (e *Encoder) BeginObject() {
+	e.buf = append(e.buf, '{')
+	e.comma = false
+}
+
+// EndObject ends the current object
+func (e *Encoder) EndObject() {
+	e.buf = append(e.buf, '}')
+	e.comma = true
+}
+
+// BeginArray starts an array. Each element is started with Element.
+func (e *Encoder) BeginArray() {
+	e.buf = append(e.buf, '[')
+	e.comma = false
+}
+
+// Element starts an element of the current array. The value is written next.
+func (e *Encoder) Element() {
+	if e.comma {
+		e.buf = append(e.buf, ',')
+	}
+	e.comma = true
+}
+
+// EndArray ends the current array
+func (e *Encoder) EndArray() {
+	e.buf = append(e.buf, ']')
+	e.comma = true
+}
+
+// Name starts a member of the current object. The value is written next.
+func (e *Encoder) Name(name string) {
+	if e.comma {
+		e.buf = append(e.buf, ',')
+	}
+	e.buf = appendString(e.buf, name)
+	e.buf = append(e.buf, ':')
+	e.comma = true
+}
+
+// String writes a string value
+func (e *Encoder) String(value string) {
+	e.buf = appendString(e.buf, value)
+}
+
+// Int writes an integer value
+func (e *Encoder) Int(value int) {
+	e.buf = strconv.AppendInt(e.buf, int64(value), 10)
+}
+
+// Null writes null
+func (e *Encoder) Null() {
+	e.buf = append(e.buf, "null"...)
+}
+
+// Raw writes JSON that was encoded elsewhere
+func (e *Encoder) Raw(value []byte) {
+	e.buf = append(e.buf, value...)
+}
+
+// Marshal writes a value encoded with the current Engine. It serves members
+// the encoder has no method for.
+func (e *Encoder) Marshal(value interface{}) error {
+	b, err := Marshal(value)
+	if err != nil {
+		return err
+	}
+	e.Raw(b)
+	return nil
+}
+
+const hex = "0123456789abcdef"
+
+// shortEscapes reports whether json.Marshal writes backspace and form feed as
+// \b and \f. Releases of Go before 1.22 write \u0008 and \u000c.
+var shortEscapes = func() bool {
+	b, err := json.Marshal("\b")
+	return err == nil && string(b) == `"\b"`
+}()
+
+// appendControl escapes a control character that has a short escape when
+// json.Marshal uses it
+func appendControl(buf []byte, c, short byte) []byte {
+	if shortEscapes {
+		return append(buf, '\\', short)
+	}
+	return append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+}
+
+// appendString quotes s the way encoding/json does. HTML characters, control
+// characters and the line separators are escaped and invalid UTF-8 is
+// replaced so the output matches json.Marshal.
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				buf = append(buf, '\\', c)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			case '\b':
+				buf = appendControl(buf, c, 'b')
+			case '\f':
+				buf = appendControl(buf, c, 'f')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
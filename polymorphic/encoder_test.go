@@ -0,0 +1,85 @@
+package polymorphic
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEncoderStrings(t *testing.T) {
+	values := []string{"", "plain", `q"b\s`, "<a&b>", "\n\r\t\b\f\x00\x1f", "é✓", "\u2028\u2029", "bad\xffutf8"}
+	for _, v := range values {
+		expected, _ := json.Marshal(v)
+		e := &Encoder{}
+		e.String(v)
+		if !bytes.Equal(e.Bytes(), expected) {
+			t.Errorf("Unexpected encoding of %q: %s expected %s", v, e.Bytes(), expected)
+		}
+	}
+}
+
+func TestEncoderControlCharacters(t *testing.T) {
+	var all []byte
+	for c := 0; c < utf8.RuneSelf; c++ {
+		all = append(all, byte(c))
+		v := "a" + string(rune(c)) + "b"
+		expected, _ := json.Marshal(v)
+		if b := appendString(nil, v); !bytes.Equal(b, expected) {
+			t.Errorf("Unexpected encoding of %q: %s expected %s", v, b, expected)
+		}
+	}
+	expected, _ := json.Marshal(string(all))
+	if b := appendString(nil, string(all)); !bytes.Equal(b, expected) {
+		t.Errorf("Unexpected encoding: %s expected %s", b, expected)
+	}
+}
+
+func TestEncoderObject(t *testing.T) {
+	e := AcquireEncoder()
+	defer ReleaseEncoder(e)
+	e.BeginObject()
+	e.Name("Kind")
+	e.String("NotFound")
+	e.Name("SchemaVersion")
+	e.Int(2)
+	e.Name("Cause")
+	e.BeginObject()
+	e.Name("Kind")
+	e.String("Fault")
+	e.Name("Cause")
+	e.Null()
+	e.EndObject()
+	e.Name("Tags")
+	err := e.Marshal([]string{"a"})
+	if err != nil {
+		t.Error("Cannot marshal", err)
+	}
+	e.Name("Causes")
+	e.BeginArray()
+	e.Element()
+	e.BeginObject()
+	e.Name("Kind")
+	e.String("Fault")
+	e.EndObject()
+	e.Element()
+	e.Null()
+	e.Element()
+	e.BeginArray()
+	e.EndArray()
+	e.EndArray()
+	e.Name("Empty")
+	e.BeginObject()
+	e.EndObject()
+	e.EndObject()
+	expected := `{"Kind":"NotFound","SchemaVersion":2,"Cause":{"Kind":"Fault","Cause":null},"Tags":["a"],` +
+		`"Causes":[{"Kind":"Fault"},null,[]],"Empty":{}}`
+	if string(e.Bytes()) != expected {
+		t.Error("Unexpected JSON", string(e.Bytes()))
+	}
+	var buf bytes.Buffer
+	_, err = e.WriteTo(&buf)
+	if err != nil || buf.String() != expected {
+		t.Error("Unexpected output", buf.String(), err)
+	}
+}
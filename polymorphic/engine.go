@@ -0,0 +1,120 @@
+package polymorphic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Engine is the JSON implementation behind the polymorphic layer. The helpers
+// of this package and the bindings call it for the values they do not read
+// or write themselves. An engine must honor json.Marshaler and
+// json.Unmarshaler so the fault types work with it unchanged. It must also
+// produce the same bytes as encoding/json. The enginetest package checks
+// both.
+type Engine interface {
+	// Marshal returns the JSON of v like json.Marshal
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal reads the JSON value in into v like json.Unmarshal. In strict
+	// mode members without a field and data after the value are errors.
+	Unmarshal(in []byte, v interface{}, strict bool) error
+	// NewDecoder returns a decoder of the stream of JSON values read from r
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder reads the tokens and values of a JSON stream
+type Decoder interface {
+	// Token returns the next delimiter or scalar like json.Decoder.Token.
+	// Delimiters are json.Delim.
+	Token() (json.Token, error)
+	// More reports whether the current array or object has more elements
+	More() bool
+	// RawValue returns the bytes of the next value without decoding it. The
+	// result is valid until the next call.
+	RawValue() ([]byte, error)
+}
+
+// StdEngine is the Engine backed by encoding/json. It is the default.
+type StdEngine struct{}
+
+var _ Engine = StdEngine{}
+
+// Marshal calls json.Marshal
+func (StdEngine) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal calls json.Unmarshal or a decoder that disallows unknown fields
+// in strict mode
+func (StdEngine) Unmarshal(in []byte, v interface{}, strict bool) error {
+	if !strict {
+		return json.Unmarshal(in, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(in))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err != nil {
+		return err
+	}
+	// More reports false before a closing bracket so only io.EOF proves that
+	// nothing follows the value
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after the JSON value")
+	}
+	return nil
+}
+
+// NewDecoder wraps json.NewDecoder
+func (StdEngine) NewDecoder(r io.Reader) Decoder {
+	return &stdDecoder{Decoder: json.NewDecoder(r)}
+}
+
+type stdDecoder struct {
+	*json.Decoder
+	raw json.RawMessage
+}
+
+func (d *stdDecoder) RawValue() ([]byte, error) {
+	err := d.Decode(&d.raw)
+	if err != nil {
+		return nil, err
+	}
+	return d.raw, nil
+}
+
+// engineHolder keeps the type stored in currentEngine the same for all engines
+type engineHolder struct {
+	Engine
+}
+
+var currentEngine atomic.Value
+
+func init() {
+	currentEngine.Store(engineHolder{StdEngine{}})
+}
+
+// SetEngine replaces the engine of all bindings. It is meant to be called
+// once at start up. Nil restores StdEngine.
+func SetEngine(e Engine) {
+	if e == nil {
+		e = StdEngine{}
+	}
+	currentEngine.Store(engineHolder{e})
+}
+
+// CurrentEngine returns the engine set with SetEngine
+func CurrentEngine() Engine {
+	return currentEngine.Load().(engineHolder).Engine
+}
+
+// Marshal writes v as JSON with the current engine
+func Marshal(v interface{}) ([]byte, error) {
+	return CurrentEngine().Marshal(v)
+}
+
+// Unmarshal reads the JSON into v with the current engine
+func Unmarshal(in []byte, v interface{}, strict bool) error {
+	return CurrentEngine().Unmarshal(in, v, strict)
+}
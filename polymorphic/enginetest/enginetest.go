@@ -0,0 +1,309 @@
+// Package enginetest checks that a polymorphic.Engine can replace
+// encoding/json. Run calls the engine directly and round trips the faults of
+// every binding package with it. All results have to match StdEngine byte for
+// byte.
+//
+//	func TestEngine(t *testing.T) {
+//		enginetest.Run(t, myEngine{})
+//	}
+//
+// Run sets the engine of all bindings for its duration so it must not run in
+// parallel with other tests that encode or decode faults.
+package enginetest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/no_accessors"
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+	"github.com/karaatanassov/go_polymorphic_json/raw_message"
+	"github.com/karaatanassov/go_polymorphic_json/type_meta"
+	"github.com/karaatanassov/go_polymorphic_json/utility_field"
+)
+
+// Run checks the engine e against StdEngine
+func Run(t *testing.T, e polymorphic.Engine) {
+	t.Run("Marshal", func(t *testing.T) { testMarshal(t, e) })
+	t.Run("Unmarshal", func(t *testing.T) { testUnmarshal(t, e) })
+	t.Run("Decoder", func(t *testing.T) { testDecoder(t, e) })
+	t.Run("RoundTrip", func(t *testing.T) { testRoundTrip(t, e) })
+}
+
+type tagged struct {
+	Name    string            `json:"name"`
+	Skipped string            `json:"-"`
+	Empty   string            `json:",omitempty"`
+	Raw     json.RawMessage   `json:"raw,omitempty"`
+	Nested  map[string]tagged `json:"nested,omitempty"`
+}
+
+// marshaler writes itself so engines are checked to honor json.Marshaler
+type marshaler struct{}
+
+func (marshaler) MarshalJSON() ([]byte, error) {
+	return []byte(` {"written" : "by marshaler"} `), nil
+}
+
+// unmarshaler records its JSON so engines are checked to honor
+// json.Unmarshaler
+type unmarshaler struct {
+	Raw string
+}
+
+func (u *unmarshaler) UnmarshalJSON(in []byte) error {
+	u.Raw = string(in)
+	return nil
+}
+
+var marshalValues = []interface{}{
+	nil, true, 42, -1.5e-7, "plain",
+	"quote \" backslash \\ html <a href=\"x\">&</a>   \x01 é \xff",
+	[]int{1, 2, 3}, []string(nil), map[string]int{"b": 2, "a": 1},
+	json.RawMessage(` { "a" : [ 1 , 2 ] } `),
+	tagged{Name: "n", Skipped: "s", Raw: json.RawMessage(`[true]`),
+		Nested: map[string]tagged{"x": {Name: "y"}}},
+	&marshaler{}, []interface{}{marshaler{}, nil},
+}
+
+func testMarshal(t *testing.T, e polymorphic.Engine) {
+	for _, v := range marshalValues {
+		expected, expectedErr := json.Marshal(v)
+		out, err := e.Marshal(v)
+		if (err == nil) != (expectedErr == nil) || !bytes.Equal(out, expected) {
+			t.Errorf("Marshal(%#v) = %s, %v expected %s, %v", v, out, err, expected, expectedErr)
+		}
+	}
+}
+
+var unmarshalCases = []struct {
+	in     string
+	strict bool
+	target func() interface{}
+}{
+	{`{"name":"n","raw":{"a":1},"nested":{"x":{"name":"y"}}}`, true, func() interface{} { return &tagged{} }},
+	{`{"NAME":"n","unknown":1}`, false, func() interface{} { return &tagged{} }},
+	{`{"name":"n","unknown":1}`, true, func() interface{} { return &tagged{} }},
+	{`{"name":"n"} {}`, true, func() interface{} { return &tagged{} }},
+	{`{"name":"n"}}`, true, func() interface{} { return &tagged{} }},
+	{`{"name":"n"}]`, true, func() interface{} { return &tagged{} }},
+	{`{"name":"n"}}`, false, func() interface{} { return &tagged{} }},
+	{`[1]]`, true, func() interface{} { return new(interface{}) }},
+	{`{"name":1}`, false, func() interface{} { return &tagged{} }},
+	{`{"name":`, false, func() interface{} { return &tagged{} }},
+	{`"é\"\n"`, false, func() interface{} { return new(string) }},
+	{` [1, {"a": null}] `, false, func() interface{} { return new(interface{}) }},
+	{`{"a":{"b":[1,2]}}`, false, func() interface{} { return &map[string]json.RawMessage{} }},
+	{` {"any" : "json"} `, true, func() interface{} { return &unmarshaler{} }},
+	{`12`, false, func() interface{} { return new(int) }},
+}
+
+// trailingInputs have data after the value. Both modes reject them.
+var trailingInputs = []string{`{"name":"n"}}`, `{"name":"n"}]`, `{"name":"n"} {}`, `[1]]`, `"s"}`}
+
+func testUnmarshal(t *testing.T, e polymorphic.Engine) {
+	for _, in := range trailingInputs {
+		for _, strict := range []bool{false, true} {
+			var v interface{}
+			if e.Unmarshal([]byte(in), &v, strict) == nil {
+				t.Errorf("Unmarshal(%s, strict %v) expected to fail on trailing data", in, strict)
+			}
+		}
+	}
+	for _, c := range unmarshalCases {
+		expected := c.target()
+		expectedErr := polymorphic.StdEngine{}.Unmarshal([]byte(c.in), expected, c.strict)
+		v := c.target()
+		err := e.Unmarshal([]byte(c.in), v, c.strict)
+		if (err == nil) != (expectedErr == nil) {
+			t.Errorf("Unmarshal(%s, strict %v) error %v expected %v", c.in, c.strict, err, expectedErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(v, expected) {
+			t.Errorf("Unmarshal(%s) = %#v expected %#v", c.in, v, expected)
+		}
+	}
+}
+
+var decoderInputs = []string{
+	`[{"a":1}, 2 ,"x", [null]]`,
+	" {\"a\":1}\n{\"b\":[2]}\n\n3 ",
+	`[]`,
+	``,
+	`[1,`,
+}
+
+// trace reads the input the way Stream does and records the tokens, values
+// and errors
+func trace(d polymorphic.Decoder) string {
+	var b strings.Builder
+	first := true
+	for i := 0; i < 100; i++ {
+		if first {
+			first = false
+			if tok, err := d.Token(); err != nil || tok != json.Delim('[') {
+				b.WriteString("token error")
+				return b.String()
+			}
+			b.WriteString("[ ")
+			continue
+		}
+		if !d.More() {
+			tok, err := d.Token()
+			if err != nil {
+				b.WriteString("end error")
+			} else if tok == json.Delim(']') {
+				b.WriteString("]")
+			}
+			return b.String()
+		}
+		raw, err := d.RawValue()
+		if err != nil {
+			b.WriteString("value error")
+			return b.String()
+		}
+		b.Write(raw)
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+// traceValues reads a stream of values until its end
+func traceValues(d polymorphic.Decoder) string {
+	var b strings.Builder
+	for i := 0; i < 100; i++ {
+		raw, err := d.RawValue()
+		if errors.Is(err, io.EOF) {
+			b.WriteString("EOF")
+			return b.String()
+		}
+		if err != nil {
+			b.WriteString("value error")
+			return b.String()
+		}
+		b.Write(raw)
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+func testDecoder(t *testing.T, e polymorphic.Engine) {
+	std := polymorphic.StdEngine{}
+	for _, in := range decoderInputs {
+		tracer := traceValues
+		if strings.HasPrefix(in, "[") {
+			tracer = trace
+		}
+		expected := tracer(std.NewDecoder(strings.NewReader(in)))
+		actual := tracer(e.NewDecoder(strings.NewReader(in)))
+		if actual != expected {
+			t.Errorf("Decoder of %q read %q expected %q", in, actual, expected)
+		}
+	}
+}
+
+// binding decodes the payloads of one binding package
+type binding struct {
+	name     string
+	decode   func(in []byte) (interface{}, error)
+	payloads []string
+}
+
+const (
+	fault = `{"Kind":"Fault","Message":"test message",` +
+		`"Cause":{"Kind":"RuntimeFault","Message":"inner message","Cause":null}}`
+	notFound = `{"Kind":"NotFound","Message":"say \"hi\" <b> é",` +
+		`"Cause":null,"ObjKind":"VirtualMachine","Obj":"vm-42"}`
+	batch = `{"Kind":"RuntimeFault","Message":"batch","Cause":null,` +
+		`"Causes":[` + notFound + `,null,` + fault + `]}`
+	faults = `[` + fault + `,null,` + notFound + `]`
+
+	metaNotFound = `{"apiVersion":"faults/v2","kind":"NotFound","message":"m & n",` +
+		`"cause":{"apiVersion":"faults/v1","kind":"Fault","message":"inner"},` +
+		`"objKind":"VirtualMachine","obj":"vm-42","objNamespace":"default"}`
+	metaList = `{"apiVersion":"v1","kind":"List","items":[` + metaNotFound + `]}`
+)
+
+var bindings = []binding{
+	{"raw_message", func(in []byte) (interface{}, error) {
+		return raw_message.UnmarshalFault(in)
+	}, []string{fault, notFound, batch}},
+	{"raw_message strict", func(in []byte) (interface{}, error) {
+		return raw_message.UnmarshalFaultStrict(in)
+	}, []string{fault, notFound, batch}},
+	{"raw_message array", func(in []byte) (interface{}, error) {
+		return raw_message.UnmarshalFaultsParallel(in, 2)
+	}, []string{faults}},
+	{"raw_message stream", func(in []byte) (interface{}, error) {
+		var res []raw_message.Fault
+		s := raw_message.NewFaultStream(bytes.NewReader(in))
+		for s.Next(context.Background()) {
+			res = append(res, s.Fault())
+		}
+		return res, s.Err()
+	}, []string{faults}},
+	{"utility_field", func(in []byte) (interface{}, error) {
+		return utility_field.UnmarshalFault(in)
+	}, []string{fault, notFound, batch}},
+	{"utility_field strict", func(in []byte) (interface{}, error) {
+		return utility_field.UnmarshalFaultStrict(in)
+	}, []string{fault, notFound, batch}},
+	{"utility_field array", func(in []byte) (interface{}, error) {
+		return utility_field.UnmarshalFaultsParallel(in, 2)
+	}, []string{faults}},
+	{"no_accessors", func(in []byte) (interface{}, error) {
+		return no_accessors.UnmarshalFault(in)
+	}, []string{fault, notFound, batch}},
+	{"no_accessors strict", func(in []byte) (interface{}, error) {
+		return no_accessors.UnmarshalFaultStrict(in)
+	}, []string{fault, notFound, batch}},
+	{"no_accessors array", func(in []byte) (interface{}, error) {
+		return no_accessors.UnmarshalFaultsParallel(in, 2)
+	}, []string{faults}},
+	{"type_meta", func(in []byte) (interface{}, error) {
+		return type_meta.UnmarshalFault(in)
+	}, []string{metaNotFound}},
+	{"type_meta list", func(in []byte) (interface{}, error) {
+		l := &type_meta.FaultList{}
+		return l, polymorphic.Unmarshal(in, l, false)
+	}, []string{metaList}},
+}
+
+// roundTrip decodes the payload and writes it back with the engine set for
+// the bindings
+func roundTrip(e polymorphic.Engine, b binding, payload string) (string, error) {
+	polymorphic.SetEngine(e)
+	defer polymorphic.SetEngine(nil)
+	v, err := b.decode([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+	out, err := e.Marshal(v)
+	return string(out), err
+}
+
+func testRoundTrip(t *testing.T, e polymorphic.Engine) {
+	for _, b := range bindings {
+		for _, payload := range b.payloads {
+			expected, err := roundTrip(polymorphic.StdEngine{}, b, payload)
+			if err != nil {
+				t.Errorf("%s: StdEngine cannot round trip %s: %v", b.name, payload, err)
+				continue
+			}
+			actual, err := roundTrip(e, b, payload)
+			if err != nil {
+				t.Errorf("%s: cannot round trip %s: %v", b.name, payload, err)
+				continue
+			}
+			if actual != expected {
+				t.Errorf("%s: round trip wrote %s expected %s", b.name, actual, expected)
+			}
+		}
+	}
+}
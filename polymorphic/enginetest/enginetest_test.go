@@ -0,0 +1,46 @@
+package enginetest
+
+import (
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+func TestStdEngine(t *testing.T) {
+	Run(t, polymorphic.StdEngine{})
+}
+
+// countingEngine delegates to StdEngine and counts the calls so the test can
+// tell the bindings went through the engine
+type countingEngine struct {
+	polymorphic.StdEngine
+	marshal, unmarshal, decoders *int64
+}
+
+func (e countingEngine) Marshal(v interface{}) ([]byte, error) {
+	atomic.AddInt64(e.marshal, 1)
+	return e.StdEngine.Marshal(v)
+}
+
+func (e countingEngine) Unmarshal(in []byte, v interface{}, strict bool) error {
+	atomic.AddInt64(e.unmarshal, 1)
+	return e.StdEngine.Unmarshal(in, v, strict)
+}
+
+func (e countingEngine) NewDecoder(r io.Reader) polymorphic.Decoder {
+	atomic.AddInt64(e.decoders, 1)
+	return e.StdEngine.NewDecoder(r)
+}
+
+func TestPluggedEngine(t *testing.T) {
+	e := countingEngine{marshal: new(int64), unmarshal: new(int64), decoders: new(int64)}
+	Run(t, e)
+	if *e.marshal == 0 || *e.unmarshal == 0 || *e.decoders == 0 {
+		t.Error("Expected the engine to be called", *e.marshal, *e.unmarshal, *e.decoders)
+	}
+	if _, ok := polymorphic.CurrentEngine().(polymorphic.StdEngine); !ok {
+		t.Error("Expected Run to restore StdEngine")
+	}
+}
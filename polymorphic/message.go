@@ -0,0 +1,279 @@
+package polymorphic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessageArg is a named argument of a localizable message. The JSON form
+// carries the type of the value next to it, like the KeyAnyValue of vSphere,
+// so integers keep their type and precision across a round trip:
+//
+//	{"Name":"count","Type":"int","Value":12345678}
+//
+// Integers are read back as int64 or uint64, floats as float64, strings and
+// booleans as they are. Values of other types are written without Type and
+// are read back as their JSON type with numbers as json.Number. Unknown
+// members of an argument are ignored in strict mode too.
+type MessageArg struct {
+	Name  string
+	Value interface{}
+}
+
+// The types of the message arguments in JSON
+const (
+	argInt    = "int"
+	argUint   = "uint"
+	argFloat  = "float"
+	argString = "string"
+	argBool   = "bool"
+)
+
+var _ json.Marshaler = MessageArg{}
+var _ json.Unmarshaler = &MessageArg{}
+
+// MarshalJSON writes the argument with the type of its value
+func (a MessageArg) MarshalJSON() ([]byte, error) {
+	return Marshal(struct {
+		Name  string
+		Type  string `json:",omitempty"`
+		Value interface{}
+	}{a.Name, argType(a.Value), a.Value})
+}
+
+// UnmarshalJSON reads the argument and converts the value to its type
+func (a *MessageArg) UnmarshalJSON(in []byte) error {
+	pxy := struct {
+		Name  string
+		Type  string
+		Value json.RawMessage
+	}{}
+	err := Unmarshal(in, &pxy, false)
+	if err != nil {
+		return err
+	}
+	value, err := argValueOf(pxy.Type, pxy.Value)
+	if err != nil {
+		return fmt.Errorf("message argument %q: %v", pxy.Name, err)
+	}
+	a.Name = pxy.Name
+	a.Value = value
+	return nil
+}
+
+// argType returns the JSON type of the value or "" if it is not a basic type
+func argType(value interface{}) string {
+	switch value.(type) {
+	case int, int8, int16, int32, int64:
+		return argInt
+	case uint, uint8, uint16, uint32, uint64:
+		return argUint
+	case float32, float64:
+		return argFloat
+	case string:
+		return argString
+	case bool:
+		return argBool
+	}
+	return ""
+}
+
+// argValueOf reads the raw value of an argument of type typ
+func argValueOf(typ string, raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	switch typ {
+	case argInt:
+		return strconv.ParseInt(string(raw), 10, 64)
+	case argUint:
+		return strconv.ParseUint(string(raw), 10, 64)
+	case argFloat:
+		return strconv.ParseFloat(string(raw), 64)
+	case argString:
+		var s string
+		err := Unmarshal(raw, &s, false)
+		return s, err
+	case argBool:
+		var b bool
+		err := Unmarshal(raw, &b, false)
+		return b, err
+	}
+	// The Engine has no option for json.Number so untyped values are always
+	// read by encoding/json to keep the digits of large numbers
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	err := dec.Decode(&value)
+	return value, err
+}
+
+// MessageCatalog renders localizable messages from per locale templates.
+// Templates refer to the arguments by name in braces:
+//
+//	vm.notFound = Virtual machine {vm} was not found
+//
+// Locales are looked up from the most specific one. A message of "de-CH" is
+// rendered with the "de-CH" templates, then "de", then the fallback locale. A
+// catalog can be shared once it is loaded.
+type MessageCatalog struct {
+	mu        sync.RWMutex
+	fallback  string
+	templates map[string]map[string]string
+}
+
+// NewMessageCatalog returns an empty catalog that falls back to the templates
+// of the fallback locale
+func NewMessageCatalog(fallback string) *MessageCatalog {
+	return &MessageCatalog{
+		fallback:  fallback,
+		templates: map[string]map[string]string{},
+	}
+}
+
+// Add adds the templates of a locale. They replace templates of the locale
+// with the same key.
+func (c *MessageCatalog) Add(locale string, templates map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.templates[locale]
+	if !ok {
+		t = map[string]string{}
+		c.templates[locale] = t
+	}
+	for key, template := range templates {
+		t[key] = template
+	}
+}
+
+// Load reads the templates of a locale from a message file. Each line holds a
+// key, an equal sign and the template. Blank lines and lines that start with
+// # are skipped.
+func (c *MessageCatalog) Load(locale string, r io.Reader) error {
+	templates := map[string]string{}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		i := strings.Index(text, "=")
+		if i <= 0 {
+			return fmt.Errorf("line %d: expected key = template", line)
+		}
+		templates[strings.TrimSpace(text[:i])] = strings.TrimSpace(text[i+1:])
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	c.Add(locale, templates)
+	return nil
+}
+
+// LoadFile reads the templates of a locale from the message file at path
+func (c *MessageCatalog) LoadFile(locale, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = c.Load(locale, f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Render returns the message of key in locale with the arguments substituted.
+// It returns defaultMessage if no locale has a template for key.
+func (c *MessageCatalog) Render(locale, key string, args []MessageArg, defaultMessage string) string {
+	if key == "" {
+		return defaultMessage
+	}
+	template, ok := c.lookup(locale, key)
+	if !ok {
+		return defaultMessage
+	}
+	return expand(template, args)
+}
+
+// lookup finds the template of key in locale, its parents or the fallback
+func (c *MessageCatalog) lookup(locale, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for {
+		if template, ok := c.templates[locale][key]; ok {
+			return template, true
+		}
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	template, ok := c.templates[c.fallback][key]
+	return template, ok
+}
+
+// expand substitutes the {name} references of the template. References to
+// missing arguments are kept as they are.
+func expand(template string, args []MessageArg) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(template[:start])
+		if value, ok := argValue(args, template[start+1:end]); ok {
+			b.WriteString(formatArg(value))
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// formatArg formats the value of an argument. Floats with integer values are
+// written with all their digits instead of an exponent.
+func formatArg(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return formatFloat(v, 64)
+	case float32:
+		return formatFloat(float64(v), 32)
+	}
+	return fmt.Sprint(value)
+}
+
+func formatFloat(f float64, bits int) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, bits)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits)
+}
+
+// argValue returns the value of the argument with name
+func argValue(args []MessageArg, name string) (interface{}, bool) {
+	for _, arg := range args {
+		if arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return nil, false
+}
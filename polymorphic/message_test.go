@@ -0,0 +1,133 @@
+package polymorphic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const deMessages = `# German messages
+vm.notFound = Virtuelle Maschine {vm} wurde nicht gefunden
+quota.exceeded = {used} von {limit} belegt
+`
+
+func TestMessageCatalogRender(t *testing.T) {
+	c := NewMessageCatalog("en")
+	c.Add("en", map[string]string{
+		"vm.notFound":    "Virtual machine {vm} was not found",
+		"quota.exceeded": "{used} of {limit} used {unknown}",
+	})
+	err := c.Load("de", strings.NewReader(deMessages))
+	if err != nil {
+		t.Error("Cannot load messages", err)
+		return
+	}
+	args := []MessageArg{{Name: "vm", Value: "vm-42"}, {Name: "used", Value: 3}, {Name: "limit", Value: 2.5}}
+	cases := []struct {
+		locale, key, expected string
+	}{
+		{"en", "vm.notFound", "Virtual machine vm-42 was not found"},
+		{"de", "vm.notFound", "Virtuelle Maschine vm-42 wurde nicht gefunden"},
+		{"de-CH", "quota.exceeded", "3 von 2.5 belegt"},
+		{"fr", "quota.exceeded", "3 of 2.5 used {unknown}"},
+		{"de", "missing", "default"},
+		{"de", "", "default"},
+	}
+	for _, c2 := range cases {
+		if s := c.Render(c2.locale, c2.key, args, "default"); s != c2.expected {
+			t.Errorf("Render(%s, %s) = %q expected %q", c2.locale, c2.key, s, c2.expected)
+		}
+	}
+}
+
+func TestMessageCatalogLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "messages")
+	if err != nil {
+		t.Error("Cannot create directory", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "de.msg")
+	err = ioutil.WriteFile(path, []byte(deMessages+"invalid line\n"), 0600)
+	if err != nil {
+		t.Error("Cannot write messages", err)
+		return
+	}
+	c := NewMessageCatalog("en")
+	err = c.LoadFile("de", path)
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Error("Expected the invalid line to fail", err)
+	}
+	if c.LoadFile("de", filepath.Join(dir, "missing.msg")) == nil {
+		t.Error("Expected a missing file to fail")
+	}
+}
+
+func TestMessageArgJSON(t *testing.T) {
+	args := []MessageArg{{Name: "vm", Value: "vm-42"}, {Name: "count", Value: 3}, {Name: "on", Value: true},
+		{Name: "big", Value: uint64(18446744073709551615)}, {Name: "ratio", Value: 2.5}, {Name: "none"}}
+	b, err := json.Marshal(args)
+	expected := `[{"Name":"vm","Type":"string","Value":"vm-42"},{"Name":"count","Type":"int","Value":3},` +
+		`{"Name":"on","Type":"bool","Value":true},{"Name":"big","Type":"uint","Value":18446744073709551615},` +
+		`{"Name":"ratio","Type":"float","Value":2.5},{"Name":"none","Value":null}]`
+	if err != nil || string(b) != expected {
+		t.Error("Unexpected JSON", string(b), err)
+	}
+	var decoded []MessageArg
+	err = Unmarshal(b, &decoded, true)
+	if err != nil || expand("{vm} {count} {on} {big} {ratio} {none}", decoded) != "vm-42 3 true 18446744073709551615 2.5 <nil>" {
+		t.Error("Unexpected arguments", decoded, err)
+	}
+	if len(decoded) != 6 || decoded[1].Value != int64(3) || decoded[4].Value != 2.5 {
+		t.Error("Expected the arguments to keep their types", decoded)
+	}
+}
+
+func TestMessageArgUntyped(t *testing.T) {
+	var decoded []MessageArg
+	err := Unmarshal([]byte(`[{"Name":"n","Value":12345678},{"Name":"f","Value":1e3},{"Name":"s","Type":"int","Value":"x"}]`), &decoded, false)
+	if err == nil || !strings.Contains(err.Error(), `message argument "s"`) {
+		t.Error("Expected a value that does not match its type to fail", err)
+	}
+	err = Unmarshal([]byte(`[{"Name":"n","Value":12345678},{"Name":"f","Value":1e3}]`), &decoded, false)
+	if err != nil || decoded[0].Value != json.Number("12345678") || expand("{n} {f}", decoded) != "12345678 1e3" {
+		t.Error("Expected untyped numbers to keep their digits", decoded, err)
+	}
+	if s := expand("{n} {f}", []MessageArg{{Name: "n", Value: float64(12345678)}, {Name: "f", Value: 1e300}}); s != "12345678 1e+300" {
+		t.Error("Unexpected float formatting", s)
+	}
+}
+
+// argEngine counts the calls to StdEngine
+type argEngine struct {
+	StdEngine
+	calls *int
+}
+
+func (e argEngine) Marshal(v interface{}) ([]byte, error) {
+	*e.calls++
+	return e.StdEngine.Marshal(v)
+}
+
+func (e argEngine) Unmarshal(in []byte, v interface{}, strict bool) error {
+	*e.calls++
+	return e.StdEngine.Unmarshal(in, v, strict)
+}
+
+func TestMessageArgEngine(t *testing.T) {
+	calls := 0
+	SetEngine(argEngine{calls: &calls})
+	defer SetEngine(StdEngine{})
+	b, err := MessageArg{Name: "vm", Value: "vm-42"}.MarshalJSON()
+	if err != nil || calls != 1 {
+		t.Error("Expected MarshalJSON to use the engine", string(b), err, calls)
+	}
+	var a MessageArg
+	err = a.UnmarshalJSON(b)
+	if err != nil || a.Value != "vm-42" || calls != 3 {
+		t.Error("Expected UnmarshalJSON to use the engine", a, err, calls)
+	}
+}
@@ -0,0 +1,301 @@
+package polymorphic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Document is a JSON object with its members kept as raw JSON. Migrations
+// change the members without decoding the nested values. The methods match
+// member names case insensitively like the decoders do, preferring an exact
+// match.
+type Document map[string]json.RawMessage
+
+// key returns the name under which the member is stored
+func (d Document) key(name string) (string, bool) {
+	if _, ok := d[name]; ok {
+		return name, true
+	}
+	found := ""
+	for key := range d {
+		if strings.EqualFold(key, name) && (found == "" || key < found) {
+			found = key
+		}
+	}
+	return found, found != ""
+}
+
+// set replaces the members matching name with a member named name
+func (d Document) set(name string, raw json.RawMessage) {
+	d.Delete(name)
+	d[name] = raw
+}
+
+// Delete removes the members matching name
+func (d Document) Delete(name string) {
+	for key := range d {
+		if strings.EqualFold(key, name) {
+			delete(d, key)
+		}
+	}
+}
+
+// String reads a string member. Missing members read as empty string.
+func (d Document) String(name string) (string, error) {
+	var s string
+	key, ok := d.key(name)
+	if !ok {
+		return s, nil
+	}
+	err := Unmarshal(d[key], &s, false)
+	return s, err
+}
+
+// SetString writes a string member. It replaces the members matching name.
+func (d Document) SetString(name, value string) {
+	raw, _ := Marshal(value)
+	d.set(name, raw)
+}
+
+// Rename moves a member to a new name. It replaces the members matching the
+// new name.
+func (d Document) Rename(name, newName string) {
+	key, ok := d.key(name)
+	if !ok {
+		return
+	}
+	raw := d[key]
+	delete(d, key)
+	d.set(newName, raw)
+}
+
+// Migration upgrades documents of Kind from SchemaVersion to the next schema
+// version. Documents that do not carry a schema version have version 0.
+type Migration struct {
+	Kind          string
+	SchemaVersion int
+	// NewKind is set when the migration renames the kind
+	NewKind string
+	// Up changes the document to the shape of the next schema version
+	Up func(doc Document) error
+	// Down is optional and reverts Up to serialise for old consumers
+	Down func(doc Document) error
+}
+
+// resultKind is the kind of documents produced by the migration
+func (m Migration) resultKind() string {
+	if m.NewKind != "" {
+		return m.NewKind
+	}
+	return m.Kind
+}
+
+type migrationKey struct {
+	kind          string
+	schemaVersion int
+}
+
+// Migrator applies the registered migrations to raw JSON before it is read
+// into the Go types.
+type Migrator struct {
+	kindProperty    string
+	versionProperty string
+
+	mu      sync.RWMutex
+	kinds   map[string]bool
+	up      map[migrationKey]Migration
+	down    map[migrationKey]Migration
+	current map[string]int
+}
+
+// NewMigrator creates a migrator for documents that carry the kind and schema
+// version in the given properties.
+func NewMigrator(kindProperty, versionProperty string) *Migrator {
+	return &Migrator{
+		kindProperty:    kindProperty,
+		versionProperty: versionProperty,
+		kinds:           make(map[string]bool),
+		up:              make(map[migrationKey]Migration),
+		down:            make(map[migrationKey]Migration),
+		current:         make(map[string]int),
+	}
+}
+
+// Register adds a migration. It panics if a migration of the same kind and
+// schema version exists.
+func (m *Migrator) Register(migration Migration) {
+	if migration.Kind == "" || migration.Up == nil {
+		panic("polymorphic: migration needs Kind and Up")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := migrationKey{migration.Kind, migration.SchemaVersion}
+	if _, ok := m.up[key]; ok {
+		panic(fmt.Sprintf("polymorphic: migration of %q from version %d exists", migration.Kind, migration.SchemaVersion))
+	}
+	m.kinds[migration.Kind] = true
+	m.up[key] = migration
+	next := migrationKey{migration.resultKind(), migration.SchemaVersion + 1}
+	m.down[next] = migration
+	if m.current[next.kind] < next.schemaVersion {
+		m.current[next.kind] = next.schemaVersion
+	}
+}
+
+// Current returns the schema version written for kind. It is 0 for kinds
+// without migrations.
+func (m *Migrator) Current(kind string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current[kind]
+}
+
+// Migrates reports whether migrations are registered for the kind bytes
+// scanned from the wire. It lets decoders skip Upgrade without allocating the
+// kind string.
+func (m *Migrator) Migrates(kind []byte) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.kinds[string(kind)]
+}
+
+// Upgrade applies the migrations registered for the document of kind until it
+// reaches the current schema. It returns the input unchanged when no
+// migration applies and the kind of the upgraded document.
+func (m *Migrator) Upgrade(in []byte, kind string) ([]byte, string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.kinds[kind] {
+		return in, kind, nil
+	}
+	doc := Document{}
+	err := Unmarshal(in, &doc, false)
+	if err != nil {
+		return nil, "", err
+	}
+	version, err := m.schemaVersion(doc)
+	if err != nil {
+		return nil, "", err
+	}
+	migrated := false
+	for migration, ok := m.up[migrationKey{kind, version}]; ok; migration, ok = m.up[migrationKey{kind, version}] {
+		err = migration.Up(doc)
+		if err != nil {
+			return nil, "", fmt.Errorf("cannot migrate %s from version %d: %v", kind, version, err)
+		}
+		kind = migration.resultKind()
+		version++
+		migrated = true
+	}
+	if !migrated {
+		return in, kind, nil
+	}
+	doc.SetString(m.kindProperty, kind)
+	raw, _ := Marshal(version)
+	doc.set(m.versionProperty, raw)
+	out, err := Marshal(doc)
+	return out, kind, err
+}
+
+// Downgrade reverts the migrations of every object in the JSON that has a
+// kind until it is at most at schemaVersion. Nested objects are downgraded as
+// well. It fails if a migration on the way has no Down function.
+func (m *Migrator) Downgrade(in []byte, schemaVersion int) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.downgrade(in, schemaVersion)
+}
+
+func (m *Migrator) downgrade(in []byte, schemaVersion int) ([]byte, error) {
+	trimmed := bytes.TrimSpace(in)
+	if len(trimmed) == 0 {
+		return in, nil
+	}
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		err := Unmarshal(trimmed, &items, false)
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			items[i], err = m.downgrade(item, schemaVersion)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return Marshal(items)
+	case '{':
+		doc := Document{}
+		err := Unmarshal(trimmed, &doc, false)
+		if err != nil {
+			return nil, err
+		}
+		for name, raw := range doc {
+			doc[name], err = m.downgrade(raw, schemaVersion)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = m.downgradeDocument(doc, schemaVersion)
+		if err != nil {
+			return nil, err
+		}
+		return Marshal(doc)
+	}
+	return in, nil
+}
+
+func (m *Migrator) downgradeDocument(doc Document, schemaVersion int) error {
+	kind, err := doc.String(m.kindProperty)
+	if err != nil || kind == "" {
+		return err
+	}
+	version, err := m.schemaVersion(doc)
+	if err != nil {
+		return err
+	}
+	if version <= schemaVersion {
+		return nil
+	}
+	for ; version > schemaVersion; version-- {
+		migration, ok := m.down[migrationKey{kind, version}]
+		if !ok {
+			break
+		}
+		if migration.Down == nil {
+			return fmt.Errorf("cannot downgrade %s from version %d", kind, version)
+		}
+		err = migration.Down(doc)
+		if err != nil {
+			return fmt.Errorf("cannot downgrade %s from version %d: %v", kind, version, err)
+		}
+		kind = migration.Kind
+	}
+	doc.SetString(m.kindProperty, kind)
+	if version == 0 {
+		doc.Delete(m.versionProperty)
+	} else {
+		raw, _ := Marshal(version)
+		doc.set(m.versionProperty, raw)
+	}
+	return nil
+}
+
+// schemaVersion reads the version of the document. Documents without version
+// have version 0.
+func (m *Migrator) schemaVersion(doc Document) (int, error) {
+	key, ok := doc.key(m.versionProperty)
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	err := Unmarshal(doc[key], &version, false)
+	if err != nil {
+		return 0, fmt.Errorf("cannot read %s: %v", m.versionProperty, err)
+	}
+	return version, nil
+}
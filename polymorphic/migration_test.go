@@ -0,0 +1,161 @@
+package polymorphic
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func newTestMigrator() *Migrator {
+	m := NewMigrator("Kind", "SchemaVersion")
+	m.Register(Migration{
+		Kind:          "Pod",
+		SchemaVersion: 0,
+		Up: func(doc Document) error {
+			doc.Rename("Name", "Title")
+			return nil
+		},
+		Down: func(doc Document) error {
+			doc.Rename("Title", "Name")
+			return nil
+		},
+	})
+	m.Register(Migration{
+		Kind:          "Pod",
+		SchemaVersion: 1,
+		NewKind:       "Workload",
+		Up:            func(doc Document) error { return nil },
+		Down:          func(doc Document) error { return nil },
+	})
+	return m
+}
+
+func decodeDocument(t *testing.T, in []byte) map[string]interface{} {
+	var doc map[string]interface{}
+	err := json.Unmarshal(in, &doc)
+	if err != nil {
+		t.Error("Cannot decode document", err)
+	}
+	return doc
+}
+
+func TestMigratorUpgrade(t *testing.T) {
+	m := newTestMigrator()
+	if m.Current("Workload") != 2 || m.Current("Pod") != 1 || m.Current("Node") != 0 {
+		t.Error("Unexpected current versions", m.Current("Workload"), m.Current("Pod"))
+	}
+	out, kind, err := m.Upgrade([]byte(`{"Kind":"Pod","Name":"web"}`), "Pod")
+	if err != nil {
+		t.Error("Cannot upgrade", err)
+		return
+	}
+	if kind != "Workload" {
+		t.Error("Unexpected kind", kind)
+	}
+	expected := map[string]interface{}{"Kind": "Workload", "SchemaVersion": 2.0, "Title": "web"}
+	if doc := decodeDocument(t, out); !reflect.DeepEqual(doc, expected) {
+		t.Error("Unexpected upgraded document", string(out))
+	}
+
+	in := []byte(`{"Kind":"Pod","SchemaVersion":1,"Title":"web"}`)
+	out, _, err = m.Upgrade(in, "Pod")
+	if err != nil {
+		t.Error("Cannot upgrade", err)
+		return
+	}
+	if doc := decodeDocument(t, out); !reflect.DeepEqual(doc, expected) {
+		t.Error("Unexpected upgraded document", string(out))
+	}
+
+	in = []byte(`{"Kind":"Node","Name":"n1"}`)
+	out, kind, err = m.Upgrade(in, "Node")
+	if err != nil || kind != "Node" || string(out) != string(in) {
+		t.Error("Expected kinds without migrations to pass through", string(out), err)
+	}
+}
+
+func TestMigratorDowngrade(t *testing.T) {
+	m := newTestMigrator()
+	in := []byte(`[{"Kind":"Workload","SchemaVersion":2,"Title":"web",` +
+		`"Cause":{"Kind":"Workload","SchemaVersion":2,"Title":"db"}}]`)
+	out, err := m.Downgrade(in, 0)
+	if err != nil {
+		t.Error("Cannot downgrade", err)
+		return
+	}
+	var docs []map[string]interface{}
+	err = json.Unmarshal(out, &docs)
+	if err != nil || len(docs) != 1 {
+		t.Error("Cannot decode downgraded documents", string(out), err)
+		return
+	}
+	expected := map[string]interface{}{
+		"Kind": "Pod",
+		"Name": "web",
+		"Cause": map[string]interface{}{
+			"Kind": "Pod",
+			"Name": "db",
+		},
+	}
+	if !reflect.DeepEqual(docs[0], expected) {
+		t.Error("Unexpected downgraded document", string(out))
+	}
+
+	out, err = m.Downgrade([]byte(`{"Kind":"Workload","SchemaVersion":2,"Title":"web"}`), 1)
+	if err != nil {
+		t.Error("Cannot downgrade", err)
+		return
+	}
+	expected = map[string]interface{}{"Kind": "Pod", "SchemaVersion": 1.0, "Title": "web"}
+	if doc := decodeDocument(t, out); !reflect.DeepEqual(doc, expected) {
+		t.Error("Unexpected downgraded document", string(out))
+	}
+}
+
+func TestMigratorMissingDown(t *testing.T) {
+	m := NewMigrator("Kind", "SchemaVersion")
+	m.Register(Migration{
+		Kind: "Pod",
+		Up:   func(doc Document) error { return nil },
+	})
+	_, err := m.Downgrade([]byte(`{"Kind":"Pod","SchemaVersion":1}`), 0)
+	if err == nil {
+		t.Error("Expected to fail downgrading without Down")
+	}
+	t.Log("Downgrade error", err)
+}
+
+func TestDocumentCaseInsensitive(t *testing.T) {
+	doc := Document{}
+	err := json.Unmarshal([]byte(`{"kind":"Pod","name":"a","Title":"t","title":"x"}`), &doc)
+	if err != nil {
+		t.Error("Cannot decode document", err)
+		return
+	}
+	if s, err := doc.String("Kind"); s != "Pod" || err != nil {
+		t.Error("Expected to read the kind case insensitively", s, err)
+	}
+	if s, _ := doc.String("Title"); s != "t" {
+		t.Error("Expected the exact match to win", s)
+	}
+	doc.SetString("Kind", "Workload")
+	doc.Rename("Name", "TITLE")
+	expected := Document{"Kind": json.RawMessage(`"Workload"`), "TITLE": json.RawMessage(`"a"`)}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Error("Expected the members to be replaced", doc)
+	}
+}
+
+func TestMigratorUpgradeCase(t *testing.T) {
+	m := newTestMigrator()
+	out, kind, err := m.Upgrade([]byte(`{"kind":"Pod","schemaVersion":0,"name":"a"}`), "Pod")
+	if err != nil || kind != "Workload" {
+		t.Error("Cannot upgrade", kind, err)
+		return
+	}
+	doc := decodeDocument(t, out)
+	expected := map[string]interface{}{"Kind": "Workload", "SchemaVersion": 2.0, "Title": "a"}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Error("Expected the kind and version to be replaced", string(out))
+	}
+}
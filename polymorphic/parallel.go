@@ -0,0 +1,109 @@
+package polymorphic
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SplitArray returns the raw elements of the JSON array in. It finds the
+// element boundaries without decoding them so the elements can be decoded
+// concurrently. The elements are validated when they are decoded. Null is
+// returned as nil.
+func SplitArray(in []byte) ([][]byte, error) {
+	s := scanner{in: in}
+	switch s.peek() {
+	case 0:
+		return nil, errUnexpectedEnd
+	case 'n':
+		if !s.consumeNull() {
+			return nil, fmt.Errorf("expected JSON array")
+		}
+		return nil, s.end()
+	case '[':
+		s.pos++
+	default:
+		return nil, fmt.Errorf("expected JSON array but found %q", s.peek())
+	}
+	elements := [][]byte{}
+	if s.peek() == ']' {
+		s.pos++
+		return elements, s.end()
+	}
+	for {
+		s.skipSpace()
+		start := s.pos
+		err := s.skipValue()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, in[start:s.pos])
+		switch c := s.peek(); c {
+		case ',':
+			s.pos++
+		case ']':
+			s.pos++
+			return elements, s.end()
+		case 0:
+			return nil, errUnexpectedEnd
+		default:
+			return nil, fmt.Errorf("invalid character %q at offset %d after array element", c, s.pos)
+		}
+	}
+}
+
+// end verifies that only white space follows the value
+func (s *scanner) end() error {
+	if c := s.peek(); c != 0 {
+		return fmt.Errorf("invalid character %q after top-level value", c)
+	}
+	return nil
+}
+
+// DecodeParallel calls decode for the indexes from 0 to n-1 in up to workers
+// goroutines. Zero or less workers use GOMAXPROCS. The indexes are handed out
+// in order and no new ones are started after a failure. The error of the
+// lowest failed index is returned as *ElementError.
+func DecodeParallel(n, workers int, decode func(i int) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	var (
+		next     int64
+		failed   int32
+		mu       sync.Mutex
+		firstErr *ElementError
+		wg       sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&failed) == 0 {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= n {
+					return
+				}
+				err := decode(i)
+				if err == nil {
+					continue
+				}
+				atomic.StoreInt32(&failed, 1)
+				mu.Lock()
+				if firstErr == nil || i < firstErr.Index {
+					firstErr = &ElementError{Index: i, Err: err}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package polymorphic
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSplitArray(t *testing.T) {
+	elements, err := SplitArray([]byte(` [ {"Kind":"a,]"} , null,[1,[2]], "x" ] `))
+	if err != nil {
+		t.Error("Cannot split array", err)
+		return
+	}
+	expected := []string{`{"Kind":"a,]"}`, `null`, `[1,[2]]`, `"x"`}
+	if len(elements) != len(expected) {
+		t.Error("Unexpected elements", len(elements))
+		return
+	}
+	for i, e := range elements {
+		if string(e) != expected[i] {
+			t.Error("Unexpected element", i, string(e))
+		}
+	}
+	elements, err = SplitArray([]byte(`[]`))
+	if err != nil || elements == nil || len(elements) != 0 {
+		t.Error("Expected an empty array", elements, err)
+	}
+	elements, err = SplitArray([]byte(`null`))
+	if err != nil || elements != nil {
+		t.Error("Expected nil for null", elements, err)
+	}
+	for _, p := range []string{``, `{}`, `[1,`, `[1 2]`, `[1]x`, `nul`} {
+		if _, err = SplitArray([]byte(p)); err == nil {
+			t.Error("Expected to fail splitting", p)
+		}
+	}
+}
+
+func TestDecodeParallel(t *testing.T) {
+	var calls int64
+	err := DecodeParallel(1000, 8, func(i int) error {
+		atomic.AddInt64(&calls, 1)
+		return nil
+	})
+	if err != nil || calls != 1000 {
+		t.Error("Expected all elements decoded", calls, err)
+	}
+
+	failure := errors.New("failure")
+	for _, workers := range []int{0, 1, 4, 64} {
+		err = DecodeParallel(1000, workers, func(i int) error {
+			if i == 300 || i == 700 {
+				return fmt.Errorf("element %d: %w", i, failure)
+			}
+			return nil
+		})
+		var ee *ElementError
+		if !errors.As(err, &ee) || ee.Index != 300 || !errors.Is(err, failure) {
+			t.Error("Expected the error of the lowest index", workers, err)
+		}
+	}
+
+	if err = DecodeParallel(0, 4, nil); err != nil {
+		t.Error("Expected no error without elements", err)
+	}
+}
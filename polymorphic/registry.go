@@ -0,0 +1,358 @@
+// Package polymorphic contains the runtime support shared by the generated
+// bindings. The bindings register their types in a Registry that drives the
+// discriminator dispatch in UnmarshalFault and the hierarchy introspection.
+package polymorphic
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Type describes a kind in a polymorphic hierarchy
+type Type struct {
+	// Version is the second discriminator for types that are selected by two
+	// properties like the Kubernetes apiVersion and kind. It is empty for
+	// types selected by kind alone.
+	Version string
+	// Kind is the discriminator value written on the wire
+	Kind string
+	// Parent is the kind this type extends. It is empty for the root. The
+	// parent has the same version as the type unless ParentVersion is set.
+	Parent string
+	// ParentVersion is the version of the parent when it differs from
+	// Version, e.g. when a type of a new version extends a kind that only
+	// exists in an older one.
+	ParentVersion string
+	// Abstract kinds are part of the hierarchy but are never written or read
+	// on the wire. Only their concrete descendants are.
+	Abstract bool
+	// Extension types are registered from outside of the package that owns
+	// the hierarchy. The bindings read them through their own UnmarshalJSON.
+	Extension bool
+	// Classification describes how failures of the kind are handled. Unset
+	// members are inherited from the parent.
+	Classification Classification
+	// New returns a pointer to a new zero value of the type
+	New func() interface{}
+}
+
+// Key identifies a registered type by the values of its discriminators
+type Key struct {
+	Version string
+	Kind    string
+}
+
+// Key returns the key the type is registered with
+func (t Type) Key() Key {
+	return Key{Version: t.Version, Kind: t.Kind}
+}
+
+// ParentKey returns the key of the parent. The result is false for roots.
+func (t Type) ParentKey() (Key, bool) {
+	if t.Parent == "" {
+		return Key{}, false
+	}
+	version := t.ParentVersion
+	if version == "" {
+		version = t.Version
+	}
+	return Key{Version: version, Kind: t.Parent}, true
+}
+
+// String formats the key as version/kind or just kind if there is no version
+func (k Key) String() string {
+	if k.Version == "" {
+		return k.Kind
+	}
+	return k.Version + "/" + k.Kind
+}
+
+// Registry holds the types of a hierarchy keyed by kind and optionally
+// version. Types are usually registered from init functions and looked up
+// concurrently afterwards. The methods that accept a kind refer to the types
+// registered without version. Their variants ending in Key accept the key of
+// any type.
+type Registry struct {
+	mu       sync.RWMutex
+	types    map[Key]*Type
+	keys     map[reflect.Type]Key
+	children map[Key][]Key
+	// kinds holds the types registered without version by kind. NewBytes
+	// looks up the kind bytes in it with m[string(b)], which Go compiles
+	// without allocating the string.
+	kinds map[string]*Type
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		types:    make(map[Key]*Type),
+		keys:     make(map[reflect.Type]Key),
+		children: make(map[Key][]Key),
+		kinds:    make(map[string]*Type),
+	}
+}
+
+// Register adds a type to the registry. The parent does not have to be
+// registered yet as bindings register their types from separate init
+// functions. Register panics if the key is already taken.
+func (r *Registry) Register(t Type) {
+	if t.Kind == "" || t.New == nil {
+		panic("polymorphic: type needs Kind and New")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := t.Key()
+	if _, ok := r.types[key]; ok {
+		panic(fmt.Sprintf("polymorphic: kind %q is already registered", key))
+	}
+	r.types[key] = &t
+	r.keys[reflect.TypeOf(t.New())] = key
+	if t.Version == "" {
+		r.kinds[t.Kind] = &t
+	}
+	if parent, ok := t.ParentKey(); ok {
+		siblings := append(r.children[parent], key)
+		sortKeys(siblings)
+		r.children[parent] = siblings
+	}
+}
+
+// New instantiates the type registered for kind. It returns nil if the kind
+// is unknown.
+func (r *Registry) New(kind string) interface{} {
+	return r.NewKey(Key{Kind: kind})
+}
+
+// NewKey instantiates the type registered for key. It returns nil if the key
+// is unknown.
+func (r *Registry) NewKey(key Key) interface{} {
+	r.mu.RLock()
+	t, ok := r.types[key]
+	r.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return t.New()
+}
+
+// NewBytes instantiates the type registered for the kind bytes scanned from
+// the wire. It does not allocate a string for the kind. It returns nil if the
+// kind is unknown.
+func (r *Registry) NewBytes(kind []byte) interface{} {
+	r.mu.RLock()
+	t, ok := r.kinds[string(kind)]
+	r.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return t.New()
+}
+
+// Lookup returns the type registered for kind
+func (r *Registry) Lookup(kind string) (Type, bool) {
+	return r.LookupKey(Key{Kind: kind})
+}
+
+// LookupKey returns the type registered for key
+func (r *Registry) LookupKey(key Key) (Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[key]
+	if !ok {
+		return Type{}, false
+	}
+	return *t, true
+}
+
+// IsAbstract reports whether kind is registered as abstract
+func (r *Registry) IsAbstract(kind string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[Key{Kind: kind}]
+	return ok && t.Abstract
+}
+
+// Kinds lists the kinds registered without version in alphabetical order
+func (r *Registry) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]string, 0, len(r.types))
+	for key := range r.types {
+		if key.Version == "" {
+			kinds = append(kinds, key.Kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Keys lists the keys of all registered types ordered by version and kind
+func (r *Registry) Keys() []Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]Key, 0, len(r.types))
+	for key := range r.types {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// sortKeys orders keys by version and kind
+func sortKeys(keys []Key) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Version != keys[j].Version {
+			return keys[i].Version < keys[j].Version
+		}
+		return keys[i].Kind < keys[j].Kind
+	})
+}
+
+// KindOf returns the kind registered for the dynamic type of v or empty string
+// if the type is not registered.
+func (r *Registry) KindOf(v interface{}) string {
+	key, _ := r.KeyOf(v)
+	return key.Kind
+}
+
+// KeyOf returns the key registered for the dynamic type of v. The result is
+// false if the type is not registered.
+func (r *Registry) KeyOf(v interface{}) (Key, bool) {
+	if v == nil {
+		return Key{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[reflect.TypeOf(v)]
+	return key, ok
+}
+
+// Parent returns the kind that kind extends. The result is false if kind is
+// unknown or is a root.
+func (r *Registry) Parent(kind string) (string, bool) {
+	parent, ok := r.ParentKey(Key{Kind: kind})
+	return parent.Kind, ok
+}
+
+// ParentKey returns the key of the type that the type of key extends. The
+// result is false if key is unknown or is a root.
+func (r *Registry) ParentKey(key Key) (Key, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[key]
+	if !ok {
+		return Key{}, false
+	}
+	return t.ParentKey()
+}
+
+// Ancestors lists the kinds that kind extends starting with its parent and
+// ending with the root.
+func (r *Registry) Ancestors(kind string) []string {
+	return kindsOf(r.AncestorsKey(Key{Kind: kind}))
+}
+
+// AncestorsKey lists the keys of the types that the type of key extends
+// starting with its parent and ending with the root.
+func (r *Registry) AncestorsKey(key Key) []Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var ancestors []Key
+	t, ok := r.types[key]
+	for ok {
+		key, ok = t.ParentKey()
+		if !ok {
+			break
+		}
+		ancestors = append(ancestors, key)
+		t, ok = r.types[key]
+	}
+	return ancestors
+}
+
+// Descendants lists the kinds that extend kind directly or indirectly. The
+// kinds are in breadth first order and alphabetical within a level.
+func (r *Registry) Descendants(kind string) []string {
+	return kindsOf(r.DescendantsKey(Key{Kind: kind}))
+}
+
+// DescendantsKey lists the keys of the types that extend the type of key
+// directly or indirectly. The keys are in breadth first order and ordered by
+// version and kind within a level.
+func (r *Registry) DescendantsKey(key Key) []Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var descendants []Key
+	level := r.children[key]
+	for len(level) > 0 {
+		descendants = append(descendants, level...)
+		var next []Key
+		for _, child := range level {
+			next = append(next, r.children[child]...)
+		}
+		level = next
+	}
+	return descendants
+}
+
+// kindsOf returns the kinds of the keys
+func kindsOf(keys []Key) []string {
+	if keys == nil {
+		return nil
+	}
+	kinds := make([]string, len(keys))
+	for i, key := range keys {
+		kinds[i] = key.Kind
+	}
+	return kinds
+}
+
+// Classify returns the classification of kind. Members the kind leaves unset
+// are taken from the nearest ancestor that sets them. Unknown kinds have the
+// zero classification.
+func (r *Registry) Classify(kind string) Classification {
+	return r.ClassifyKey(Key{Kind: kind})
+}
+
+// ClassifyKey returns the classification of the type of key like Classify
+func (r *Registry) ClassifyKey(key Key) Classification {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var c Classification
+	t, ok := r.types[key]
+	for ok {
+		c = c.inherit(t.Classification)
+		key, ok = t.ParentKey()
+		if ok {
+			t, ok = r.types[key]
+		}
+	}
+	return c
+}
+
+// IsA reports whether kind is ancestorKind or one of its descendants. Unknown
+// kinds are not related to any kind.
+func (r *Registry) IsA(kind, ancestorKind string) bool {
+	return r.IsAKey(Key{Kind: kind}, Key{Kind: ancestorKind})
+}
+
+// IsAKey reports whether the type of key is the type of ancestor or one of
+// its descendants. Unknown keys are not related to any key.
+func (r *Registry) IsAKey(key, ancestor Key) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[key]
+	for ok {
+		if key == ancestor {
+			return true
+		}
+		key, ok = t.ParentKey()
+		if ok {
+			t, ok = r.types[key]
+		}
+	}
+	return false
+}
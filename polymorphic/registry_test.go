@@ -0,0 +1,166 @@
+package polymorphic
+
+import (
+	"reflect"
+	"testing"
+)
+
+type animal struct{}
+type mammal struct{ animal }
+type cat struct{ mammal }
+type dog struct{ mammal }
+type bird struct{ animal }
+
+func newTestRegistry() *Registry {
+	r := NewRegistry()
+	// Children are registered before their parents on purpose
+	r.Register(Type{Kind: "Cat", Parent: "Mammal", New: func() interface{} { return &cat{} }})
+	r.Register(Type{Kind: "Dog", Parent: "Mammal", New: func() interface{} { return &dog{} }})
+	r.Register(Type{Kind: "Mammal", Parent: "Animal", New: func() interface{} { return &mammal{} }})
+	r.Register(Type{Kind: "Bird", Parent: "Animal", New: func() interface{} { return &bird{} }})
+	r.Register(Type{Kind: "Animal", Abstract: true, New: func() interface{} { return &animal{} }})
+	return r
+}
+
+func TestRegistryNew(t *testing.T) {
+	r := newTestRegistry()
+	if _, ok := r.New("Cat").(*cat); !ok {
+		t.Error("Expected to instantiate cat")
+	}
+	if v := r.New("Fish"); v != nil {
+		t.Error("Expected nil for unknown kind", v)
+	}
+	if kind := r.KindOf(&dog{}); kind != "Dog" {
+		t.Error("Unexpected kind of dog:", kind)
+	}
+	if kind := r.KindOf(dog{}); kind != "" {
+		t.Error("Expected no kind for dog value:", kind)
+	}
+}
+
+func TestRegistryHierarchy(t *testing.T) {
+	r := newTestRegistry()
+	if parent, ok := r.Parent("Cat"); !ok || parent != "Mammal" {
+		t.Error("Unexpected parent of Cat:", parent)
+	}
+	if _, ok := r.Parent("Animal"); ok {
+		t.Error("Expected Animal to be root")
+	}
+	ancestors := r.Ancestors("Cat")
+	if !reflect.DeepEqual(ancestors, []string{"Mammal", "Animal"}) {
+		t.Error("Unexpected ancestors of Cat:", ancestors)
+	}
+	descendants := r.Descendants("Animal")
+	if !reflect.DeepEqual(descendants, []string{"Bird", "Mammal", "Cat", "Dog"}) {
+		t.Error("Unexpected descendants of Animal:", descendants)
+	}
+	if len(r.Descendants("Dog")) != 0 {
+		t.Error("Expected no descendants of Dog")
+	}
+	if !r.IsA("Cat", "Animal") || !r.IsA("Cat", "Cat") {
+		t.Error("Expected Cat to be an Animal")
+	}
+	if r.IsA("Bird", "Mammal") || r.IsA("Fish", "Animal") {
+		t.Error("Unexpected IsA relation")
+	}
+}
+
+func TestRegistryDuplicate(t *testing.T) {
+	r := newTestRegistry()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected duplicate registration to panic")
+		}
+	}()
+	r.Register(Type{Kind: "Cat", New: func() interface{} { return &cat{} }})
+}
+
+func TestRegistryAbstract(t *testing.T) {
+	r := newTestRegistry()
+	if !r.IsAbstract("Animal") {
+		t.Error("Expected Animal to be abstract")
+	}
+	if r.IsAbstract("Cat") || r.IsAbstract("Fish") {
+		t.Error("Expected Cat and unknown kinds to be concrete")
+	}
+	typ, ok := r.Lookup("Animal")
+	if !ok || !typ.Abstract || typ.Kind != "Animal" {
+		t.Error("Unexpected type for Animal:", typ)
+	}
+	if _, ok := r.Lookup("Fish"); ok {
+		t.Error("Expected no type for unknown kind")
+	}
+}
+
+func TestRegistryVersions(t *testing.T) {
+	r := newTestRegistry()
+	r.Register(Type{Version: "v2", Kind: "Cat", New: func() interface{} { return &struct{ cat }{} }})
+	if _, ok := r.NewKey(Key{Version: "v2", Kind: "Cat"}).(*struct{ cat }); !ok {
+		t.Error("Expected to instantiate versioned cat")
+	}
+	if _, ok := r.New("Cat").(*cat); !ok {
+		t.Error("Expected to instantiate unversioned cat")
+	}
+	key, ok := r.KeyOf(&struct{ cat }{})
+	if !ok || key.String() != "v2/Cat" {
+		t.Error("Unexpected key", key)
+	}
+	if len(r.Kinds()) != 5 || len(r.Keys()) != 6 {
+		t.Error("Unexpected kinds", r.Kinds(), r.Keys())
+	}
+	if r.Keys()[5].Version != "v2" {
+		t.Error("Expected versioned keys last", r.Keys())
+	}
+}
+
+func TestRegistryClassify(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Type{Kind: "Fault", New: func() interface{} { return &animal{} },
+		Classification: Classification{Retry: NotRetryable, Severity: SeverityError, HTTPStatus: 500, ExitCode: 1}})
+	r.Register(Type{Kind: "Busy", Parent: "Fault", New: func() interface{} { return &mammal{} },
+		Classification: Classification{Retry: Retryable, HTTPStatus: 503}})
+	r.Register(Type{Kind: "Throttled", Parent: "Busy", New: func() interface{} { return &cat{} },
+		Classification: Classification{HTTPStatus: 429}})
+	expected := Classification{Retry: Retryable, Severity: SeverityError, HTTPStatus: 429, ExitCode: 1}
+	if c := r.Classify("Throttled"); c != expected || !c.IsRetryable() {
+		t.Error("Unexpected classification", c)
+	}
+	if c := r.Classify("Fault"); c.IsRetryable() || c.Severity.String() != "error" {
+		t.Error("Unexpected classification of the root", c)
+	}
+	if c := r.Classify("Unknown"); c != (Classification{}) {
+		t.Error("Expected the zero classification", c)
+	}
+}
+
+func TestRegistryVersionedHierarchy(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Type{Version: "v1", Kind: "Animal", Classification: Classification{HTTPStatus: 500},
+		New: func() interface{} { return &animal{} }})
+	r.Register(Type{Version: "v1", Kind: "Mammal", Parent: "Animal", New: func() interface{} { return &mammal{} }})
+	r.Register(Type{Version: "v2", Kind: "Cat", Parent: "Mammal", ParentVersion: "v1",
+		New: func() interface{} { return &cat{} }})
+	r.Register(Type{Version: "v2", Kind: "Kitten", Parent: "Cat", New: func() interface{} { return &struct{ cat }{} }})
+	kitten := Key{Version: "v2", Kind: "Kitten"}
+	animalKey := Key{Version: "v1", Kind: "Animal"}
+	expected := []Key{{"v2", "Cat"}, {"v1", "Mammal"}, animalKey}
+	if ancestors := r.AncestorsKey(kitten); !reflect.DeepEqual(ancestors, expected) {
+		t.Error("Unexpected ancestors", ancestors)
+	}
+	expected = []Key{{"v1", "Mammal"}, {"v2", "Cat"}, kitten}
+	if descendants := r.DescendantsKey(animalKey); !reflect.DeepEqual(descendants, expected) {
+		t.Error("Unexpected descendants", descendants)
+	}
+	if parent, ok := r.ParentKey(Key{Version: "v2", Kind: "Cat"}); !ok || parent != (Key{"v1", "Mammal"}) {
+		t.Error("Unexpected parent", parent)
+	}
+	if !r.IsAKey(kitten, animalKey) || r.IsAKey(animalKey, kitten) || r.IsAKey(kitten, Key{Kind: "Animal"}) {
+		t.Error("Unexpected IsAKey")
+	}
+	if r.ClassifyKey(kitten).HTTPStatus != 500 {
+		t.Error("Expected the classification of the v1 root", r.ClassifyKey(kitten))
+	}
+	if r.IsA("Kitten", "Animal") || r.Ancestors("Kitten") != nil {
+		t.Error("Expected the kind methods to refer to unversioned types")
+	}
+}
@@ -0,0 +1,131 @@
+package polymorphic
+
+import (
+	"context"
+	"time"
+)
+
+// Clock waits for the backoff between attempts. Tests replace it so retries
+// run without sleeping.
+type Clock interface {
+	// After returns a channel that receives once d has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock waits with the time package
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// RetryRule overrides how failures of a kind and its descendants are retried
+type RetryRule struct {
+	// Retry overrides the retryability of the classification of the kind.
+	// RetryUnspecified keeps it.
+	Retry Retryability
+	// Backoff is the delay before the first retry. Zero uses the
+	// InitialBackoff of the policy.
+	Backoff time.Duration
+}
+
+// RetryPolicy bounds the attempts and the backoff of Retry. Zero members use
+// the defaults: 3 attempts and a backoff that starts at 100ms and doubles up
+// to 10s.
+type RetryPolicy struct {
+	// MaxAttempts counts the first call too
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Multiplier grows the backoff after each retry. Values below 1 use 2.
+	Multiplier float64
+	// Rules are keyed by kind. The rule of the nearest ancestor applies to
+	// kinds without their own rule.
+	Rules map[string]RetryRule
+	// Clock waits between attempts. Nil uses SystemClock.
+	Clock Clock
+}
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultMultiplier     = 2
+)
+
+// withDefaults replaces the zero members with the defaults
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaultMultiplier
+	}
+	if p.Clock == nil {
+		p.Clock = SystemClock
+	}
+	return p
+}
+
+// Rule returns the rule of the first of kinds that has one. Bindings pass a
+// kind followed by its ancestors.
+func (p RetryPolicy) Rule(kinds ...string) RetryRule {
+	for _, kind := range kinds {
+		if rule, ok := p.Rules[kind]; ok {
+			return rule
+		}
+	}
+	return RetryRule{}
+}
+
+// Backoff returns the delay after the failed attempt. The first attempt is
+// 1. first is the delay before the first retry or zero for InitialBackoff.
+func (p RetryPolicy) Backoff(first time.Duration, attempt int) time.Duration {
+	p = p.withDefaults()
+	if first <= 0 {
+		first = p.InitialBackoff
+	}
+	d := float64(first)
+	for i := 1; i < attempt && d < float64(p.MaxBackoff); i++ {
+		d *= p.Multiplier
+	}
+	if d > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(d)
+}
+
+// Retry calls fn until it succeeds, decide returns a rule that is not
+// Retryable or the attempts of the policy are used up. It returns the last
+// error of fn or the error of ctx if ctx ends while waiting. The bindings
+// provide decide from the classification of the fault in the error.
+func Retry(ctx context.Context, policy RetryPolicy, decide func(err error) RetryRule,
+	fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := fn(ctx)
+		if err == nil || attempt >= policy.MaxAttempts {
+			return err
+		}
+		rule := decide(err)
+		if rule.Retry != Retryable {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-policy.Clock.After(policy.Backoff(rule.Backoff, attempt)):
+		}
+	}
+}
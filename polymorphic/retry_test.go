@@ -0,0 +1,111 @@
+package polymorphic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock records the delays and does not wait
+type fakeClock struct {
+	delays []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+var errTransient = errors.New("transient")
+
+func decideTransient(err error) RetryRule {
+	if err == errTransient {
+		return RetryRule{Retry: Retryable}
+	}
+	return RetryRule{Retry: NotRetryable}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	clock := &fakeClock{}
+	calls := 0
+	err := Retry(context.Background(), RetryPolicy{MaxAttempts: 5, MaxBackoff: 300 * time.Millisecond, Clock: clock},
+		decideTransient, func(ctx context.Context) error {
+			calls++
+			return errTransient
+		})
+	if err != errTransient || calls != 5 {
+		t.Error("Expected all attempts to fail", err, calls)
+	}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	if len(clock.delays) != len(expected) {
+		t.Error("Unexpected delays", clock.delays)
+		return
+	}
+	for i := range expected {
+		if clock.delays[i] != expected[i] {
+			t.Error("Unexpected delays", clock.delays)
+		}
+	}
+}
+
+func TestRetryStops(t *testing.T) {
+	clock := &fakeClock{}
+	calls := 0
+	terminal := errors.New("terminal")
+	err := Retry(context.Background(), RetryPolicy{Clock: clock}, decideTransient, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return errTransient
+		}
+		return terminal
+	})
+	if err != terminal || calls != 2 || len(clock.delays) != 1 {
+		t.Error("Expected to stop at the terminal error", err, calls)
+	}
+	calls = 0
+	err = Retry(context.Background(), RetryPolicy{Clock: clock}, decideTransient, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Error("Expected to succeed on the third attempt", err, calls)
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Retry(ctx, RetryPolicy{Clock: &fakeClock{}}, decideTransient, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errTransient
+	})
+	if err != context.Canceled || calls != 1 {
+		t.Error("Expected the context to stop the retries", err, calls)
+	}
+}
+
+func TestRetryRule(t *testing.T) {
+	p := RetryPolicy{Rules: map[string]RetryRule{
+		"RuntimeFault": {Backoff: time.Second},
+		"NotFound":     {Retry: Retryable},
+	}}
+	if p.Rule("Timeout", "RuntimeFault", "Fault").Backoff != time.Second {
+		t.Error("Expected the rule of the ancestor")
+	}
+	if p.Rule("NotFound", "RuntimeFault", "Fault").Retry != Retryable {
+		t.Error("Expected the rule of the kind")
+	}
+	if p.Rule("Fault") != (RetryRule{}) {
+		t.Error("Expected no rule")
+	}
+	if d := p.Backoff(time.Second, 3); d != 4*time.Second {
+		t.Error("Unexpected backoff", d)
+	}
+}
@@ -0,0 +1,238 @@
+package polymorphic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var null = []byte("null")
+
+var errUnexpectedEnd = errors.New("unexpected end of JSON input")
+
+// scanner walks the members of a JSON object without decoding the values it
+// skips. It only checks the syntax of the bytes it reads. The values are
+// validated when they are decoded into the Go types.
+type scanner struct {
+	in  []byte
+	pos int
+}
+
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.in) {
+		switch s.in[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next byte that is not white space or 0 at the end
+func (s *scanner) peek() byte {
+	s.skipSpace()
+	if s.pos >= len(s.in) {
+		return 0
+	}
+	return s.in[s.pos]
+}
+
+func (s *scanner) expect(c byte) error {
+	next := s.peek()
+	if next == 0 {
+		return errUnexpectedEnd
+	}
+	if next != c {
+		return fmt.Errorf("invalid character %q at offset %d, expected %q", next, s.pos, c)
+	}
+	s.pos++
+	return nil
+}
+
+// consumeNull moves past null. It returns false if the next value is not null.
+func (s *scanner) consumeNull() bool {
+	if s.peek() != 'n' || !bytes.HasPrefix(s.in[s.pos:], null) {
+		return false
+	}
+	s.pos += len(null)
+	return true
+}
+
+// readString reads a quoted string. It returns the bytes including the quotes
+// and whether they contain escape sequences.
+func (s *scanner) readString() ([]byte, bool, error) {
+	err := s.expect('"')
+	if err != nil {
+		return nil, false, err
+	}
+	start := s.pos - 1
+	escaped := false
+	for s.pos < len(s.in) {
+		switch s.in[s.pos] {
+		case '\\':
+			escaped = true
+			s.pos += 2
+			continue
+		case '"':
+			s.pos++
+			return s.in[start:s.pos], escaped, nil
+		}
+		s.pos++
+	}
+	return nil, false, errUnexpectedEnd
+}
+
+// skipValue moves past the next value. Nested objects and arrays are skipped
+// by counting the brackets outside of strings.
+func (s *scanner) skipValue() error {
+	depth := 0
+	for {
+		c := s.peek()
+		switch c {
+		case 0:
+			return errUnexpectedEnd
+		case '"':
+			_, _, err := s.readString()
+			if err != nil {
+				return err
+			}
+		case '{', '[':
+			depth++
+			s.pos++
+		case '}', ']':
+			depth--
+			s.pos++
+		case ',', ':':
+			if depth == 0 {
+				return fmt.Errorf("invalid character %q at offset %d", c, s.pos)
+			}
+			s.pos++
+		default:
+			start := s.pos
+			for s.pos < len(s.in) && isLiteral(s.in[s.pos]) {
+				s.pos++
+			}
+			if s.pos == start {
+				return fmt.Errorf("invalid character %q at offset %d", c, s.pos)
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+		if depth < 0 {
+			return fmt.Errorf("invalid character %q at offset %d", c, s.pos-1)
+		}
+	}
+}
+
+// isLiteral reports whether c can be part of a number, true, false or null
+func isLiteral(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.' || c == 'E'
+}
+
+// unquote converts the quoted bytes into a string. Only strings with escape
+// sequences go through the Engine.
+func unquote(quoted []byte, escaped bool) (string, error) {
+	if !escaped {
+		return string(quoted[1 : len(quoted)-1]), nil
+	}
+	var s string
+	err := Unmarshal(quoted, &s, false)
+	return s, err
+}
+
+// equalFold compares the quoted member name with name ignoring ASCII case
+// without allocating. Names with escape sequences are unquoted first.
+func equalFold(quoted []byte, escaped bool, name string) bool {
+	if escaped {
+		s, err := unquote(quoted, escaped)
+		return err == nil && asciiEqualFold(s, name)
+	}
+	member := quoted[1 : len(quoted)-1]
+	if len(member) != len(name) {
+		return false
+	}
+	for i := 0; i < len(member); i++ {
+		if lower(member[i]) != lower(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func asciiEqualFold(s, name string) bool {
+	if len(s) != len(name) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if lower(s[i]) != lower(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// readStringValue reads the value of the named member. Null reads as empty
+// like encoding/json does. The result is a slice of the input unless the
+// string has escape sequences.
+func (s *scanner) readStringValue(name string) ([]byte, error) {
+	switch s.peek() {
+	case '"':
+		quoted, escaped, err := s.readString()
+		if err != nil {
+			return nil, err
+		}
+		if !escaped {
+			return quoted[1 : len(quoted)-1], nil
+		}
+		value, err := unquote(quoted, escaped)
+		return []byte(value), err
+	case 'n':
+		if s.consumeNull() {
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot read %s: expected string at offset %d", name, s.pos)
+}
+
+// openObject moves past the opening brace of an object. The result is false
+// if the value is null.
+func (s *scanner) openObject() (bool, error) {
+	switch s.peek() {
+	case 0:
+		return false, errUnexpectedEnd
+	case 'n':
+		if !s.consumeNull() {
+			return false, fmt.Errorf("expected JSON object")
+		}
+		return false, nil
+	case '{':
+		s.pos++
+		return true, nil
+	}
+	return false, fmt.Errorf("expected JSON object but found %q", s.peek())
+}
+
+// readMember reads the quoted name of the next member and the colon after it
+func (s *scanner) readMember() ([]byte, bool, error) {
+	name, escaped, err := s.readString()
+	if err != nil {
+		return nil, false, err
+	}
+	return name, escaped, s.expect(':')
+}
+
+// skipComma moves past the comma between members
+func (s *scanner) skipComma() {
+	if s.peek() == ',' {
+		s.pos++
+	}
+}
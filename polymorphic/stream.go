@@ -0,0 +1,149 @@
+package polymorphic
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ElementError reports the index of the element in a stream that failed
+type ElementError struct {
+	Index int
+	Err   error
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error of the element
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// Stream reads the elements of a JSON array or of a stream of values such as
+// NDJSON one at a time. Only the current element is held in memory so it
+// serves inputs larger than the memory.
+//
+//	s := polymorphic.NewStream(r)
+//	for s.Next(ctx) {
+//		process(s.Raw())
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type Stream struct {
+	r     *bufio.Reader
+	dec   Decoder
+	array bool
+	index int
+	raw   []byte
+	err   error
+}
+
+// NewStream creates a stream over r. The first byte of the input selects
+// between a JSON array and a stream of values.
+func NewStream(r io.Reader) *Stream {
+	return &Stream{r: bufio.NewReader(r), index: -1}
+}
+
+// Next reads the next element. It returns false at the end of the input, on
+// error and when ctx is done. Err tells these apart.
+func (s *Stream) Next(ctx context.Context) bool {
+	if s.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		s.err = err
+		return false
+	}
+	if s.dec == nil {
+		s.err = s.open()
+		if s.err != nil {
+			return false
+		}
+	}
+	if s.array && !s.dec.More() {
+		s.err = s.close()
+		return false
+	}
+	raw, err := s.dec.RawValue()
+	if err == io.EOF && !s.array {
+		s.err = io.EOF
+		return false
+	}
+	s.index++
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = &ElementError{Index: s.index, Err: err}
+		return false
+	}
+	s.raw = raw
+	return true
+}
+
+// open detects the shape of the input and consumes the opening bracket of an
+// array. Empty input is an empty stream.
+func (s *Stream) open() error {
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		err = s.r.UnreadByte()
+		if err != nil {
+			return err
+		}
+		s.dec = CurrentEngine().NewDecoder(s.r)
+		if c != '[' {
+			return nil
+		}
+		s.array = true
+		_, err = s.dec.Token()
+		return err
+	}
+}
+
+// close consumes the closing bracket of the array and verifies nothing but
+// white space follows.
+func (s *Stream) close() error {
+	_, err := s.dec.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.dec.Token()
+	if err == nil {
+		return fmt.Errorf("unexpected data after the JSON array")
+	}
+	return err
+}
+
+// Raw returns the JSON of the current element. It is only valid until the
+// next call to Next.
+func (s *Stream) Raw() json.RawMessage {
+	return s.raw
+}
+
+// Index returns the position of the current element starting from 0
+func (s *Stream) Index() int {
+	return s.index
+}
+
+// Err returns the error that stopped the stream or nil at the end of input
+func (s *Stream) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
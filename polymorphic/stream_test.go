@@ -0,0 +1,83 @@
+package polymorphic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func readStream(ctx context.Context, in string) ([]string, error) {
+	s := NewStream(strings.NewReader(in))
+	var elements []string
+	for s.Next(ctx) {
+		if s.Index() != len(elements) {
+			return nil, errors.New("unexpected index")
+		}
+		elements = append(elements, string(s.Raw()))
+	}
+	return elements, s.Err()
+}
+
+func TestStream(t *testing.T) {
+	payloads := map[string]int{
+		` [{"Kind":"Fault"}, {"Kind":"NotFound"} ,null] `: 3,
+		"{\"Kind\":\"Fault\"}\n{\"Kind\":\"NotFound\"}\n": 2,
+		`[]`: 0,
+		``:   0,
+		"\n": 0,
+	}
+	for p, count := range payloads {
+		elements, err := readStream(context.Background(), p)
+		if err != nil {
+			t.Error("Cannot read stream", p, err)
+			continue
+		}
+		if len(elements) != count {
+			t.Error("Unexpected elements", elements, "in", p)
+		}
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	payloads := map[string]int{
+		`[{"Kind":"Fault"},{"Kind":}]`:          1,
+		"{\"Kind\":\"Fault\"}\n{\"Kind\":\"Not": 1,
+		`[{"Kind":"Fault"}`:                     1,
+		`[{"Kind":"Fault"}] []`:                 -1,
+	}
+	for p, index := range payloads {
+		_, err := readStream(context.Background(), p)
+		if err == nil {
+			t.Error("Expected to fail reading", p)
+			continue
+		}
+		t.Log("Stream error", err)
+		var elementErr *ElementError
+		if errors.As(err, &elementErr) != (index >= 0) {
+			t.Error("Unexpected error type", err)
+			continue
+		}
+		if index >= 0 && elementErr.Index != index {
+			t.Error("Unexpected index", elementErr.Index, "in", p)
+		}
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewStream(strings.NewReader(`[1,2,3]`))
+	if !s.Next(ctx) {
+		t.Error("Expected the first element", s.Err())
+	}
+	cancel()
+	if s.Next(ctx) {
+		t.Error("Expected the stream to stop")
+	}
+	if s.Err() != context.Canceled {
+		t.Error("Unexpected error", s.Err())
+	}
+	if s.Next(context.Background()) {
+		t.Error("Expected the stream to stay stopped")
+	}
+}
@@ -0,0 +1,525 @@
+package polymorphic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxDepth is the nesting limit of Parse. It matches encoding/json.
+const maxDepth = 10000
+
+// Value is a JSON value indexed by Parse. Objects and arrays keep their
+// members so nested faults are decoded from the index without scanning their
+// bytes again. Decoding a tree of nested faults this way parses each byte a
+// bounded number of times independent of the depth of the tree.
+type Value struct {
+	// Raw holds the bytes of the value
+	Raw      []byte
+	members  []Member
+	elements []Value
+}
+
+// Member is a member of an indexed JSON object
+type Member struct {
+	// name is the quoted name as it is on the wire
+	name    []byte
+	escaped bool
+	Value   Value
+}
+
+// Name returns the unquoted member name
+func (m *Member) Name() string {
+	name, _ := unquote(m.name, m.escaped)
+	return name
+}
+
+// Parse indexes the JSON value in. It validates the syntax of the whole
+// value like encoding/json does before decoding.
+func Parse(in []byte) (*Value, error) {
+	p := &parser{
+		scanner: scanner{in: in},
+		members: make([]Member, 0, 16),
+	}
+	v := &Value{}
+	err := p.value(v, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c := p.peek(); c != 0 {
+		return nil, fmt.Errorf("invalid character %q after top-level value", c)
+	}
+	return v, nil
+}
+
+// IsNull reports whether the value is null
+func (v *Value) IsNull() bool {
+	return bytes.Equal(v.Raw, null)
+}
+
+// IsObject reports whether the value is an object
+func (v *Value) IsObject() bool {
+	return len(v.Raw) > 0 && v.Raw[0] == '{'
+}
+
+// IsArray reports whether the value is an array
+func (v *Value) IsArray() bool {
+	return len(v.Raw) > 0 && v.Raw[0] == '['
+}
+
+// Members returns the members of an object in the order on the wire
+func (v *Value) Members() []Member {
+	return v.members
+}
+
+// Elements returns the elements of an array
+func (v *Value) Elements() []Value {
+	return v.elements
+}
+
+// Member returns the last member matching name case insensitively or nil.
+// The last one is the one Decode and encoding/json keep.
+func (v *Value) Member(name string) *Value {
+	for i := len(v.members) - 1; i >= 0; i-- {
+		m := &v.members[i]
+		if equalFold(m.name, m.escaped, name) {
+			return &m.Value
+		}
+	}
+	return nil
+}
+
+var valueType = reflect.TypeOf(&Value{})
+
+// Decode reads the object into the flat struct pointed by pxy. Members are
+// matched to the exported fields by name or json tag like encoding/json does.
+// Fields of type *Value receive the indexed member so nested values can be
+// decoded later without scanning them again. Other fields are decoded with
+// the current Engine. In strict mode members without a field are errors and the
+// fields are decoded with unknown fields disallowed.
+func (v *Value) Decode(pxy interface{}, strict bool) error {
+	rv := reflect.ValueOf(pxy)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode into %T", pxy)
+	}
+	if v.IsNull() {
+		return nil
+	}
+	rv = rv.Elem()
+	if !v.IsObject() {
+		return &json.UnmarshalTypeError{Value: describe(v.Raw), Type: rv.Type()}
+	}
+	fields := cachedFields(rv.Type())
+	for i := range v.members {
+		m := &v.members[i]
+		index := fields.match(m)
+		if index < 0 {
+			if strict {
+				return fmt.Errorf("json: unknown field %q", m.Name())
+			}
+			continue
+		}
+		field := rv.Field(index)
+		if field.Type() == valueType {
+			field.Set(reflect.ValueOf(&m.Value))
+			continue
+		}
+		if decodeScalar(m.Value.Raw, field) {
+			continue
+		}
+		err := Unmarshal(m.Value.Raw, field.Addr().Interface(), strict)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckMembers returns an error for the first member of the object that is
+// not one of names and has no exported field in the struct pointed by s or in
+// the structs it embeds. Names are matched like Decode does. Types that read
+// themselves with their own UnmarshalJSON are checked with it in strict mode.
+func (v *Value) CheckMembers(s interface{}, names ...string) error {
+	t := reflect.TypeOf(s)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot check the members of %T", s)
+	}
+	fs := embeddedFields(t.Elem(), nil)
+	for i := range v.members {
+		m := &v.members[i]
+		if fs.match(m) >= 0 || matchesAny(m, names) {
+			continue
+		}
+		return fmt.Errorf("json: unknown field %q", m.Name())
+	}
+	return nil
+}
+
+// matchesAny reports whether the member matches one of names
+func matchesAny(m *Member, names []string) bool {
+	for _, name := range names {
+		if equalFold(m.name, m.escaped, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// embeddedFields appends the exported fields of the struct and of the
+// structs it embeds without a name in their json tag. The index of the
+// fields is not set.
+func embeddedFields(t reflect.Type, fs fields) fields {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		tagName := strings.Split(tag, ",")[0]
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && tagName == "" && ft.Kind() == reflect.Struct {
+			fs = embeddedFields(ft, fs)
+			continue
+		}
+		if sf.PkgPath != "" || tagName == "-" {
+			continue
+		}
+		name := sf.Name
+		if tagName != "" {
+			name = tagName
+		}
+		fs = append(fs, field{name: name})
+	}
+	return fs
+}
+
+// decodeScalar sets strings without escape sequences, integers and booleans
+// directly. These are most of the fault members and encoding/json would
+// allocate a decoder for each of them. It returns false for the other values.
+func decodeScalar(raw []byte, field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.String:
+		if raw[0] != '"' || bytes.IndexByte(raw, '\\') >= 0 || !utf8.Valid(raw) {
+			return false
+		}
+		field.SetString(string(raw[1 : len(raw)-1]))
+		return true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(string(raw), 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetInt(i)
+		return true
+	case reflect.Bool:
+		if raw[0] != 't' && raw[0] != 'f' {
+			return false
+		}
+		field.SetBool(raw[0] == 't')
+		return true
+	}
+	return false
+}
+
+// describe names the JSON type of raw for errors
+func describe(raw []byte) string {
+	switch raw[0] {
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	}
+	return "number"
+}
+
+// field is an exported struct field that members decode into
+type field struct {
+	name  string
+	index int
+}
+
+type fields []field
+
+// match returns the index of the field for the member or -1. An exact name
+// match wins over a case insensitive one.
+func (fs fields) match(m *Member) int {
+	name := m.name[1 : len(m.name)-1]
+	if m.escaped {
+		name = []byte(m.Name())
+	}
+	fold := -1
+	for _, f := range fs {
+		if string(name) == f.name {
+			return f.index
+		}
+		if fold < 0 && asciiEqualFold(string(name), f.name) {
+			fold = f.index
+		}
+	}
+	return fold
+}
+
+var fieldCache sync.Map
+
+func cachedFields(t reflect.Type) fields {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.(fields)
+	}
+	var fs fields
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := sf.Name
+		if tag := sf.Tag.Get("json"); tag != "" {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fs = append(fs, field{name: name, index: i})
+	}
+	fieldCache.Store(t, fs)
+	return fs
+}
+
+// parser builds the index in a single pass over the bytes. Members and
+// elements are collected on stacks shared by all nesting levels and copied
+// into a slice of the exact size when their object or array ends.
+type parser struct {
+	scanner
+	members  []Member
+	elements []Value
+}
+
+func (p *parser) value(v *Value, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("exceeded max depth")
+	}
+	var err error
+	c := p.peek()
+	start := p.pos
+	switch c {
+	case 0:
+		return errUnexpectedEnd
+	case '{':
+		err = p.object(v, depth)
+	case '[':
+		err = p.array(v, depth)
+	case '"':
+		_, err = p.str()
+	case 't':
+		err = p.literal("true")
+	case 'f':
+		err = p.literal("false")
+	case 'n':
+		err = p.literal("null")
+	default:
+		err = p.number()
+	}
+	if err != nil {
+		return err
+	}
+	v.Raw = p.in[start:p.pos]
+	return nil
+}
+
+func (p *parser) object(v *Value, depth int) error {
+	p.pos++
+	if p.peek() == '}' {
+		p.pos++
+		return nil
+	}
+	base := len(p.members)
+	defer func() { p.members = p.members[:base] }()
+	for {
+		if p.peek() != '"' {
+			return p.unexpected("beginning of object key string")
+		}
+		start := p.pos
+		escaped, err := p.str()
+		if err != nil {
+			return err
+		}
+		m := Member{name: p.in[start:p.pos], escaped: escaped}
+		if p.peek() != ':' {
+			return p.unexpected("after object key")
+		}
+		p.pos++
+		err = p.value(&m.Value, depth+1)
+		if err != nil {
+			return err
+		}
+		p.members = append(p.members, m)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			v.members = append([]Member(nil), p.members[base:]...)
+			return nil
+		default:
+			return p.unexpected("after object key:value pair")
+		}
+	}
+}
+
+func (p *parser) array(v *Value, depth int) error {
+	p.pos++
+	if p.peek() == ']' {
+		p.pos++
+		return nil
+	}
+	base := len(p.elements)
+	defer func() { p.elements = p.elements[:base] }()
+	for {
+		var element Value
+		err := p.value(&element, depth+1)
+		if err != nil {
+			return err
+		}
+		p.elements = append(p.elements, element)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			v.elements = append([]Value(nil), p.elements[base:]...)
+			return nil
+		default:
+			return p.unexpected("after array element")
+		}
+	}
+}
+
+// str validates a string and reports whether it has escape sequences
+func (p *parser) str() (bool, error) {
+	p.pos++
+	escaped := false
+	for p.pos < len(p.in) {
+		c := p.in[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return escaped, nil
+		case c == '\\':
+			escaped = true
+			err := p.escape()
+			if err != nil {
+				return false, err
+			}
+			continue
+		case c < 0x20:
+			return false, p.unexpected("in string literal")
+		}
+		p.pos++
+	}
+	return false, errUnexpectedEnd
+}
+
+func (p *parser) escape() error {
+	p.pos++
+	if p.pos >= len(p.in) {
+		return errUnexpectedEnd
+	}
+	switch p.in[p.pos] {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		p.pos++
+		return nil
+	case 'u':
+		p.pos++
+		for i := 0; i < 4; i++ {
+			if p.pos >= len(p.in) {
+				return errUnexpectedEnd
+			}
+			if !isHex(p.in[p.pos]) {
+				return p.unexpected("in \\u hexadecimal character escape")
+			}
+			p.pos++
+		}
+		return nil
+	}
+	return p.unexpected("in string escape code")
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func (p *parser) literal(lit string) error {
+	if !bytes.HasPrefix(p.in[p.pos:], []byte(lit)) {
+		if len(p.in)-p.pos < len(lit) && bytes.HasPrefix([]byte(lit), p.in[p.pos:]) {
+			return errUnexpectedEnd
+		}
+		return p.unexpected("in literal " + lit)
+	}
+	p.pos += len(lit)
+	return nil
+}
+
+// number validates the JSON number grammar
+func (p *parser) number() error {
+	if p.pos < len(p.in) && p.in[p.pos] == '-' {
+		p.pos++
+	}
+	if p.pos >= len(p.in) {
+		return errUnexpectedEnd
+	}
+	switch c := p.in[p.pos]; {
+	case c == '0':
+		p.pos++
+	case c >= '1' && c <= '9':
+		p.digits()
+	default:
+		return p.unexpected("looking for beginning of value")
+	}
+	if p.pos < len(p.in) && p.in[p.pos] == '.' {
+		p.pos++
+		if p.digits() == 0 {
+			return p.unexpectedOrEnd("after decimal point in numeric literal")
+		}
+	}
+	if p.pos < len(p.in) && (p.in[p.pos] == 'e' || p.in[p.pos] == 'E') {
+		p.pos++
+		if p.pos < len(p.in) && (p.in[p.pos] == '+' || p.in[p.pos] == '-') {
+			p.pos++
+		}
+		if p.digits() == 0 {
+			return p.unexpectedOrEnd("in exponent of numeric literal")
+		}
+	}
+	return nil
+}
+
+func (p *parser) digits() int {
+	start := p.pos
+	for p.pos < len(p.in) && p.in[p.pos] >= '0' && p.in[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+func (p *parser) unexpectedOrEnd(context string) error {
+	if p.pos >= len(p.in) {
+		return errUnexpectedEnd
+	}
+	return p.unexpected(context)
+}
+
+func (p *parser) unexpected(context string) error {
+	if p.pos >= len(p.in) {
+		return errUnexpectedEnd
+	}
+	return fmt.Errorf("invalid character %q %s", p.in[p.pos], context)
+}
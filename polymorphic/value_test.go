@@ -0,0 +1,139 @@
+package polymorphic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseValidates(t *testing.T) {
+	payloads := []string{
+		`{}`, `[]`, `null`, `true`, `false`, `0`, `-0.5e+10`, `"aé\n"`,
+		` {"a":[1,{"b":null}],"c":"}","d":{}} `,
+		``, `{`, `{"a"}`, `{"a":}`, `{"a":1,}`, `[1,]`, `[1 2]`, `{"a":1}x`,
+		`01`, `1.`, `-`, `1e`, `nul`, `tru`, `"\x"`, `"\u12"`, "\"a\tb\"", `{a:1}`,
+	}
+	for _, p := range payloads {
+		_, err := Parse([]byte(p))
+		if (err == nil) != json.Valid([]byte(p)) {
+			t.Error("Parse and json.Valid disagree on", p, err)
+		}
+	}
+	deep := strings.Repeat("[", maxDepth+2) + strings.Repeat("]", maxDepth+2)
+	if _, err := Parse([]byte(deep)); err == nil {
+		t.Error("Expected to fail on nesting deeper than", maxDepth)
+	}
+}
+
+func TestParseIndex(t *testing.T) {
+	v, err := Parse([]byte(`{"Kind":"Fault","Cause":{"Kind":"RuntimeFault"},"List":[1,"a"]}`))
+	if err != nil {
+		t.Error("Cannot parse", err)
+		return
+	}
+	if len(v.Members()) != 3 || v.Members()[1].Name() != "Cause" {
+		t.Error("Unexpected members", v.Members())
+	}
+	cause := v.Member("cause")
+	if cause == nil || string(cause.Member("Kind").Raw) != `"RuntimeFault"` {
+		t.Error("Unexpected cause", cause)
+	}
+	list := v.Member("List")
+	if list == nil || len(list.Elements()) != 2 || string(list.Elements()[1].Raw) != `"a"` {
+		t.Error("Unexpected list", list)
+	}
+	if v.Member("Missing") != nil {
+		t.Error("Expected no member")
+	}
+}
+
+func TestValueDecode(t *testing.T) {
+	v, err := Parse([]byte(`{"kind":"NotFound","Message":"m","obj":"vm-42",` +
+		`"ObjKind":"VirtualMachine","objKind":"Ignored","Cause":{"Kind":"Fault"},"Extra":1}`))
+	if err != nil {
+		t.Error("Cannot parse", err)
+		return
+	}
+	pxy := &struct {
+		Kind    string
+		Message string
+		Obj     string `json:"obj"`
+		ObjKind string
+		Cause   *Value
+		Skipped string `json:"-"`
+	}{}
+	err = v.Decode(pxy, false)
+	if err != nil {
+		t.Error("Cannot decode", err)
+		return
+	}
+	if pxy.Kind != "NotFound" || pxy.Message != "m" || pxy.Obj != "vm-42" {
+		t.Error("Unexpected proxy", pxy)
+	}
+	if pxy.ObjKind != "Ignored" {
+		t.Error("Expected the last duplicate to win like encoding/json", pxy.ObjKind)
+	}
+	if pxy.Cause == nil || string(pxy.Cause.Raw) != `{"Kind":"Fault"}` {
+		t.Error("Expected the indexed cause", pxy.Cause)
+	}
+	err = v.Decode(pxy, true)
+	if err == nil {
+		t.Error("Expected strict decode to fail on Extra")
+	}
+	t.Log("Strict error", err)
+
+	v, _ = Parse([]byte(`[]`))
+	if err = v.Decode(pxy, false); err == nil {
+		t.Error("Expected to fail decoding an array into a struct")
+	}
+	v, _ = Parse([]byte(`{"Message":1}`))
+	if err = v.Decode(pxy, false); err == nil {
+		t.Error("Expected to fail decoding a number into a string")
+	}
+}
+
+type checkedBase struct {
+	Message string
+}
+
+type checkedStruct struct {
+	checkedBase
+	Limit  int    `json:"limit"`
+	Hidden string `json:"-"`
+}
+
+func TestCheckMembers(t *testing.T) {
+	v, err := Parse([]byte(`{"Kind":"Quota","message":"m","limit":1}`))
+	if err != nil {
+		t.Error("Cannot parse", err)
+		return
+	}
+	if err = v.CheckMembers(&checkedStruct{}, "Kind"); err != nil {
+		t.Error("Expected the members to match", err)
+	}
+	if err = v.CheckMembers(&checkedStruct{}); err == nil || err.Error() != `json: unknown field "Kind"` {
+		t.Error("Expected Kind to be unknown", err)
+	}
+	v, _ = Parse([]byte(`{"Hidden":"h"}`))
+	if err = v.CheckMembers(&checkedStruct{}); err == nil {
+		t.Error("Expected the ignored field to be unknown")
+	}
+	if err = v.CheckMembers(checkedStruct{}); err == nil {
+		t.Error("Expected a struct value to fail")
+	}
+}
+
+func TestValueMemberLast(t *testing.T) {
+	v, err := Parse([]byte(`{"Kind":"NotFound","kind":"Fault"}`))
+	if err != nil {
+		t.Error("Cannot parse", err)
+		return
+	}
+	if m := v.Member("Kind"); m == nil || string(m.Raw) != `"Fault"` {
+		t.Error("Expected the last member", m)
+	}
+	kind, ok, err := Discriminator{KindProperty: "Kind"}.ValueKind(v)
+	if !ok || err != nil || string(kind) != "Fault" {
+		t.Error("Unexpected kind", string(kind), err)
+	}
+}
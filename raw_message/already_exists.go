@@ -0,0 +1,181 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// AlreadyExists represents error when the object to create already exists
+type AlreadyExists interface {
+	RuntimeFault
+	GetObjKind() string
+	SetObjKind(string)
+	GetObj() string
+	SetObj(string)
+	// alreadyExists seals the interface. Types outside of this package
+	// implement it by embedding AlreadyExistsStruct.
+	alreadyExists()
+}
+
+// AlreadyExistsStruct contains the data about an object that already exists
+type AlreadyExistsStruct struct {
+	RuntimeFaultStruct
+	ObjKind string
+	Obj     string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "AlreadyExists",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &AlreadyExistsStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 409,
+			ExitCode:   73, // EX_CANTCREAT
+		},
+	})
+}
+
+var _ AlreadyExists = &AlreadyExistsStruct{}
+var _ RuntimeFault = &AlreadyExistsStruct{}
+var _ Fault = &AlreadyExistsStruct{}
+var _ json.Marshaler = &AlreadyExistsStruct{}
+var _ json.Unmarshaler = &AlreadyExistsStruct{}
+var _ fmt.Formatter = &AlreadyExistsStruct{}
+
+// NewAlreadyExists creates an AlreadyExists for the object of objKind with id
+// obj. The options set the members inherited from FaultStruct like the message.
+func NewAlreadyExists(objKind, obj string, opts ...Option) *AlreadyExistsStruct {
+	x := &AlreadyExistsStruct{ObjKind: objKind, Obj: obj}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (ae *AlreadyExistsStruct) GetKind() string {
+	return "AlreadyExists"
+}
+
+// Error formats the fault with its kind, message, the object that already
+// exists and the cause
+func (ae *AlreadyExistsStruct) Error() string {
+	details := strings.TrimSpace(ae.ObjKind + " " + ae.Obj)
+	return formatError("AlreadyExists", ae.Message, details, ae.Cause, ae.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (ae *AlreadyExistsStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, ae)
+}
+
+// alreadyExists is a marker to prevent converting struct with same fields
+// into AlreadyExists interface
+func (ae *AlreadyExistsStruct) alreadyExists() {
+}
+
+// GetObjKind retrieves the kind of the object that already exists
+func (ae *AlreadyExistsStruct) GetObjKind() string {
+	return ae.ObjKind
+}
+
+// SetObjKind sets the kind of the object that already exists
+func (ae *AlreadyExistsStruct) SetObjKind(objKind string) {
+	ae.ObjKind = objKind
+}
+
+// GetObj retrieves the id of the object that already exists
+func (ae *AlreadyExistsStruct) GetObj() string {
+	return ae.Obj
+}
+
+// SetObj sets the id of the object that already exists
+func (ae *AlreadyExistsStruct) SetObj(obj string) {
+	ae.Obj = obj
+}
+
+// MarshalJSON writes AlreadyExists as JSON
+func (ae *AlreadyExistsStruct) MarshalJSON() ([]byte, error) {
+	return marshal(ae)
+}
+
+// encode writes the Kind first and then the members directly
+func (ae *AlreadyExistsStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "AlreadyExists")
+	if err != nil {
+		return err
+	}
+	err = ae.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(ae.ObjKind)
+	e.Name("Obj")
+	e.String(ae.Obj)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (ae *AlreadyExistsStruct) UnmarshalJSON(in []byte) error {
+	return unmarshal(ae, in, false)
+}
+
+func (ae *AlreadyExistsStruct) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		ObjKind       string
+		Obj           string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "AlreadyExists", strict)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	ae.Message = pxy.Message
+	ae.MessageKey = pxy.MessageKey
+	ae.MessageArgs = pxy.MessageArgs
+	ae.Cause = cause
+	ae.Causes = causes
+	ae.ObjKind = pxy.ObjKind
+	ae.Obj = pxy.Obj
+	return nil
+}
+
+// UnmarshalAlreadyExists reads AlreadyExists or its subclasses from JSON bytes
+func UnmarshalAlreadyExists(in []byte) (AlreadyExists, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyExists, ok := fault.(AlreadyExists); ok {
+		return alreadyExists, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal AlreadyExists from %v", fault)
+}
@@ -0,0 +1,151 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// baselineUnmarshalFault is UnmarshalFault of the baseline before the
+// discriminator scan. It decodes the value once to find the Kind and once more
+// into the proxy of the kind. The Cause is a json.RawMessage that is decoded
+// again the same way.
+func baselineUnmarshalFault(in []byte) (Fault, error) {
+	d := &struct {
+		Kind string
+	}{}
+	// Double pointer detects null values
+	err := json.Unmarshal(in, &d)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, nil
+	}
+	// The proxy of NotFound. The proxies of the other kinds lack ObjKind and
+	// Obj.
+	pxy := &struct {
+		Message string
+		Cause   json.RawMessage
+		ObjKind string
+		Obj     string
+	}{}
+	err = json.Unmarshal(in, pxy)
+	if err != nil {
+		return nil, err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = baselineUnmarshalFault(pxy.Cause)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch d.Kind {
+	case "NotFound":
+		nf := &NotFoundStruct{ObjKind: pxy.ObjKind, Obj: pxy.Obj}
+		nf.Message = pxy.Message
+		nf.Cause = cause
+		return nf, nil
+	case "RuntimeFault":
+		rf := &RuntimeFaultStruct{}
+		rf.Message = pxy.Message
+		rf.Cause = cause
+		return rf, nil
+	}
+	return &FaultStruct{Message: pxy.Message, Cause: cause}, nil
+}
+
+func TestBaselineUnmarshalFault(t *testing.T) {
+	b, err := json.Marshal(notFound)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	fault, err := baselineUnmarshalFault(b)
+	if err != nil {
+		t.Error("Cannot deserialize fault", err)
+		return
+	}
+	validateNotFound(fault, t)
+}
+
+func benchmarkUnmarshal(b *testing.B, unmarshal func([]byte) (Fault, error)) {
+	in, err := json.Marshal(notFound)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(in)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = unmarshal(in)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalFault(b *testing.B) {
+	benchmarkUnmarshal(b, UnmarshalFault)
+}
+
+func BenchmarkBaselineUnmarshalFault(b *testing.B) {
+	benchmarkUnmarshal(b, baselineUnmarshalFault)
+}
+
+// causeChain returns the JSON of a NotFound with a chain of depth causes
+func causeChain(tb testing.TB, depth int) []byte {
+	var cause *NotFoundStruct
+	for i := 0; i < depth; i++ {
+		nf := *notFound
+		if cause != nil {
+			nf.Cause = cause
+		} else {
+			nf.Cause = nil
+		}
+		cause = &nf
+	}
+	in, err := json.Marshal(cause)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return in
+}
+
+func TestUnmarshalCauseChain(t *testing.T) {
+	fault, err := UnmarshalFaultStrict(causeChain(t, 64))
+	if err != nil {
+		t.Error("Cannot deserialize cause chain", err)
+		return
+	}
+	depth := 0
+	for fault != nil {
+		nf, ok := fault.(NotFound)
+		if !ok {
+			t.Error("Unexpected type", fault)
+			return
+		}
+		depth++
+		fault = nf.GetCause()
+	}
+	if depth != 64 {
+		t.Error("Unexpected depth", depth)
+	}
+}
+
+func BenchmarkUnmarshalCauseChain(b *testing.B) {
+	for _, depth := range []int{1, 8, 32, 128} {
+		in := causeChain(b, depth)
+		b.Run(fmt.Sprintf("depth-%d", depth), func(b *testing.B) {
+			b.SetBytes(int64(len(in)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := UnmarshalFault(in)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// catalog holds a fault of each kind of the standard catalog
+var catalog = []Fault{
+	&InvalidArgumentStruct{RuntimeFaultStruct: *runtimeFault, Argument: "spec.memory"},
+	&AlreadyExistsStruct{RuntimeFaultStruct: *runtimeFault, ObjKind: "VirtualMachine", Obj: "vm-42"},
+	&PermissionDeniedStruct{RuntimeFaultStruct: *runtimeFault, Privilege: "VirtualMachine.PowerOn"},
+	&TimeoutStruct{RuntimeFaultStruct: *runtimeFault, Operation: "PowerOn"},
+	&ConflictStruct{RuntimeFaultStruct: *runtimeFault, ObjKind: "VirtualMachine", Obj: "vm-42"},
+	&ResourceExhaustedStruct{RuntimeFaultStruct: *runtimeFault, Resource: "memory"},
+}
+
+// narrow calls the narrowing function of each kind of the catalog
+var narrow = []func(in []byte) error{
+	func(in []byte) error { _, err := UnmarshalInvalidArgument(in); return err },
+	func(in []byte) error { _, err := UnmarshalAlreadyExists(in); return err },
+	func(in []byte) error { _, err := UnmarshalPermissionDenied(in); return err },
+	func(in []byte) error { _, err := UnmarshalTimeout(in); return err },
+	func(in []byte) error { _, err := UnmarshalConflict(in); return err },
+	func(in []byte) error { _, err := UnmarshalResourceExhausted(in); return err },
+}
+
+func TestCatalogRoundTrip(t *testing.T) {
+	for _, f := range catalog {
+		b, err := json.Marshal(f)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			continue
+		}
+		decoded, err := UnmarshalFaultStrict(b)
+		if err != nil {
+			t.Error("Cannot deserialize", string(b), err)
+			continue
+		}
+		if !reflect.DeepEqual(decoded, f) {
+			t.Error("Unexpected fault", string(b))
+		}
+		if _, ok := decoded.(RuntimeFault); !ok || !IsA(KindOf(decoded), "RuntimeFault") {
+			t.Error("Expected a RuntimeFault", decoded.GetKind())
+		}
+	}
+}
+
+func TestCatalogNarrowing(t *testing.T) {
+	for i, f := range catalog {
+		b, err := json.Marshal(f)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			continue
+		}
+		for j, unmarshal := range narrow {
+			if err := unmarshal(b); (err == nil) != (i == j) {
+				t.Error("Unexpected narrowing of", f.GetKind(), j, err)
+			}
+		}
+	}
+}
+
+func TestCatalogError(t *testing.T) {
+	expected := []string{
+		"InvalidArgument: test message (spec.memory): RuntimeFault: inner message",
+		"AlreadyExists: test message (VirtualMachine vm-42): RuntimeFault: inner message",
+		"PermissionDenied: test message (VirtualMachine.PowerOn): RuntimeFault: inner message",
+		"Timeout: test message (PowerOn): RuntimeFault: inner message",
+		"Conflict: test message (VirtualMachine vm-42): RuntimeFault: inner message",
+		"ResourceExhausted: test message (memory): RuntimeFault: inner message",
+	}
+	for i, f := range catalog {
+		if f.Error() != expected[i] {
+			t.Error("Unexpected error string", f.Error())
+		}
+	}
+}
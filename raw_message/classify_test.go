@@ -0,0 +1,42 @@
+package raw_message
+
+import (
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// vmNotFound is not registered. It is classified by the kind it reports.
+type vmNotFound struct {
+	NotFoundStruct
+}
+
+func TestClassify(t *testing.T) {
+	expected := polymorphic.Classification{
+		Retry:      polymorphic.NotRetryable,
+		Severity:   polymorphic.SeverityWarning,
+		HTTPStatus: 404,
+		ExitCode:   66,
+	}
+	decoded, err := UnmarshalFault([]byte(notFoundJSON))
+	if err != nil {
+		t.Error("Cannot deserialize", err)
+		return
+	}
+	for _, f := range []Fault{notFound, decoded, &vmNotFound{}} {
+		if c := Classify(f); c != expected {
+			t.Error("Unexpected classification", f.GetKind(), c)
+		}
+	}
+	timeout := Classify(&TimeoutStruct{})
+	if !timeout.IsRetryable() || timeout.Severity != polymorphic.SeverityError ||
+		timeout.HTTPStatus != 504 || timeout.ExitCode != 75 {
+		t.Error("Expected Timeout to inherit from RuntimeFault and Fault", timeout)
+	}
+	if !IsRetryable(runtimeFault) || IsRetryable(fault) || IsRetryable(notFound) || IsRetryable(nil) {
+		t.Error("Unexpected retryability")
+	}
+	if ClassifyKind("Conflict").HTTPStatus != 409 || ClassifyKind("Missing") != (polymorphic.Classification{}) {
+		t.Error("Unexpected classification of kinds")
+	}
+}
@@ -0,0 +1,180 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// Conflict represents error when the request conflicts with the current state
+// of an object such as a concurrent modification
+type Conflict interface {
+	RuntimeFault
+	GetObjKind() string
+	SetObjKind(string)
+	GetObj() string
+	SetObj(string)
+	// conflict seals the interface. Types outside of this package
+	// implement it by embedding ConflictStruct.
+	conflict()
+}
+
+// ConflictStruct contains the data about a conflicting object
+type ConflictStruct struct {
+	RuntimeFaultStruct
+	ObjKind string
+	Obj     string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "Conflict",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &ConflictStruct{} },
+		Classification: polymorphic.Classification{
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 409,
+		},
+	})
+}
+
+var _ Conflict = &ConflictStruct{}
+var _ RuntimeFault = &ConflictStruct{}
+var _ Fault = &ConflictStruct{}
+var _ json.Marshaler = &ConflictStruct{}
+var _ json.Unmarshaler = &ConflictStruct{}
+var _ fmt.Formatter = &ConflictStruct{}
+
+// NewConflict creates a Conflict for the object of objKind with id obj.
+// The options set the members inherited from FaultStruct like the message.
+func NewConflict(objKind, obj string, opts ...Option) *ConflictStruct {
+	x := &ConflictStruct{ObjKind: objKind, Obj: obj}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (cf *ConflictStruct) GetKind() string {
+	return "Conflict"
+}
+
+// Error formats the fault with its kind, message, the object in conflict
+// and the cause
+func (cf *ConflictStruct) Error() string {
+	details := strings.TrimSpace(cf.ObjKind + " " + cf.Obj)
+	return formatError("Conflict", cf.Message, details, cf.Cause, cf.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (cf *ConflictStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, cf)
+}
+
+// conflict is a marker to prevent converting struct with same fields
+// into Conflict interface
+func (cf *ConflictStruct) conflict() {
+}
+
+// GetObjKind retrieves the kind of the object in conflict
+func (cf *ConflictStruct) GetObjKind() string {
+	return cf.ObjKind
+}
+
+// SetObjKind sets the kind of the object in conflict
+func (cf *ConflictStruct) SetObjKind(objKind string) {
+	cf.ObjKind = objKind
+}
+
+// GetObj retrieves the id of the object in conflict
+func (cf *ConflictStruct) GetObj() string {
+	return cf.Obj
+}
+
+// SetObj sets the id of the object in conflict
+func (cf *ConflictStruct) SetObj(obj string) {
+	cf.Obj = obj
+}
+
+// MarshalJSON writes Conflict as JSON
+func (cf *ConflictStruct) MarshalJSON() ([]byte, error) {
+	return marshal(cf)
+}
+
+// encode writes the Kind first and then the members directly
+func (cf *ConflictStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "Conflict")
+	if err != nil {
+		return err
+	}
+	err = cf.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(cf.ObjKind)
+	e.Name("Obj")
+	e.String(cf.Obj)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (cf *ConflictStruct) UnmarshalJSON(in []byte) error {
+	return unmarshal(cf, in, false)
+}
+
+func (cf *ConflictStruct) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		ObjKind       string
+		Obj           string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "Conflict", strict)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	cf.Message = pxy.Message
+	cf.MessageKey = pxy.MessageKey
+	cf.MessageArgs = pxy.MessageArgs
+	cf.Cause = cause
+	cf.Causes = causes
+	cf.ObjKind = pxy.ObjKind
+	cf.Obj = pxy.Obj
+	return nil
+}
+
+// UnmarshalConflict reads Conflict or its subclasses from JSON bytes
+func UnmarshalConflict(in []byte) (Conflict, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if conflict, ok := fault.(Conflict); ok {
+		return conflict, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal Conflict from %v", fault)
+}
@@ -0,0 +1,163 @@
+package raw_message
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// FromError converts an error chain into faults that can be sent as JSON.
+// Faults in the chain are returned as they are, with their causes. Any other
+// error becomes a FaultStruct. Its message is the message of the error without
+// the message of the cause that fmt.Errorf("...: %w") appends. Its cause is
+// the wrapped error, converted the same way.
+//
+// An error that wraps several errors, such as the result of errors.Join,
+// becomes a FaultStruct with the wrapped errors as its Causes. The message of
+// that FaultStruct is empty when the error only joins the messages of its
+// causes.
+func FromError(err error) Fault {
+	if err == nil {
+		return nil
+	}
+	if f, ok := err.(Fault); ok {
+		if isNil(f) {
+			return nil
+		}
+		return f
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return &FaultStruct{Message: joinMessage(err, u.Unwrap()), Causes: fromErrors(u.Unwrap())}
+	case interface{ Unwrap() error }:
+		cause := u.Unwrap()
+		return &FaultStruct{Message: wrapperMessage(err, cause), Cause: FromError(cause)}
+	}
+	return &FaultStruct{Message: err.Error()}
+}
+
+// fromErrors converts the errors that are not nil into faults
+func fromErrors(errs []error) []Fault {
+	var res []Fault
+	for _, err := range errs {
+		if err != nil {
+			res = append(res, FromError(err))
+		}
+	}
+	return res
+}
+
+// joinMessage returns the message of an error that wraps errs. It is empty
+// when the message only joins the messages of errs with new lines as
+// errors.Join does, so they are not repeated next to the causes.
+func joinMessage(err error, errs []error) string {
+	var messages []string
+	for _, e := range errs {
+		if e != nil {
+			messages = append(messages, e.Error())
+		}
+	}
+	message := err.Error()
+	if message == strings.Join(messages, "\n") {
+		return ""
+	}
+	return message
+}
+
+// wrapperMessage returns the message of err without the message of its cause
+func wrapperMessage(err, cause error) string {
+	message := err.Error()
+	if cause == nil {
+		return message
+	}
+	return strings.TrimSuffix(message, ": "+cause.Error())
+}
+
+// formatError writes the kind and the message followed by the details of the
+// kind in parentheses, the error of the cause and the errors of the causes in
+// brackets
+func formatError(kind, message, details string, cause Fault, causes []Fault) string {
+	var b strings.Builder
+	b.WriteString(kind)
+	if message != "" {
+		b.WriteString(": ")
+		b.WriteString(message)
+	}
+	if details != "" {
+		b.WriteString(" (")
+		b.WriteString(details)
+		b.WriteString(")")
+	}
+	if !isNil(cause) {
+		b.WriteString(": ")
+		b.WriteString(cause.Error())
+	}
+	if len(causes) > 0 {
+		b.WriteString(" [")
+		for i, c := range causes {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			if isNil(c) {
+				b.WriteString("<nil>")
+			} else {
+				b.WriteString(c.Error())
+			}
+		}
+		b.WriteString("]")
+	}
+	return b.String()
+}
+
+// unwrapCause returns the cause as error or nil. A nil pointer to a fault
+// struct is returned as nil so errors.Unwrap chains end there.
+func unwrapCause(cause Fault) error {
+	if isNil(cause) {
+		return nil
+	}
+	return cause
+}
+
+// unwrapAll lists the cause and the causes that are not nil
+func unwrapAll(cause Fault, causes []Fault) []error {
+	var res []error
+	if !isNil(cause) {
+		res = append(res, cause)
+	}
+	for _, c := range causes {
+		if !isNil(c) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// isInCauses reports whether target is in the chain of one of the causes
+func isInCauses(causes []Fault, target error) bool {
+	for _, c := range causes {
+		if !isNil(c) && errors.Is(c, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// asInCauses assigns the first error in the chains of the causes that matches
+// target
+func asInCauses(causes []Fault, target interface{}) bool {
+	for _, c := range causes {
+		if !isNil(c) && errors.As(c, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNil reports whether the fault is nil or a nil pointer to a fault struct
+func isNil(f Fault) bool {
+	if f == nil {
+		return true
+	}
+	v := reflect.ValueOf(f)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,134 @@
+package raw_message
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// RegisterExtension adds a kind defined outside of this package to the
+// hierarchy. The Fault interfaces are sealed with unexported markers so an
+// extension has to embed the struct of its parent kind e.g.
+//
+//	type QuotaExceededStruct struct {
+//		raw_message.RuntimeFaultStruct
+//		Limit int
+//	}
+//
+// The extension needs its own GetKind, MarshalJSON and UnmarshalJSON as the
+// ones promoted from the parent struct write and read the parent kind. Error
+// and Format are promoted too and print the parent kind unless the extension
+// defines its own. RegisterExtension panics if the extension breaks these
+// rules.
+func RegisterExtension(t polymorphic.Type) {
+	err := checkExtension(t)
+	if err != nil {
+		panic(fmt.Sprintf("raw_message: cannot register %q: %v", t.Kind, err))
+	}
+	t.Extension = true
+	registry.Register(t)
+}
+
+// checkExtension verifies that the extension embeds the struct of its parent
+// and reports and writes its own kind.
+func checkExtension(t polymorphic.Type) error {
+	parent, ok := registry.Lookup(t.Parent)
+	if !ok {
+		return fmt.Errorf("unknown parent kind %q", t.Parent)
+	}
+	if t.New == nil {
+		return fmt.Errorf("missing New")
+	}
+	f, ok := t.New().(Fault)
+	if !ok {
+		return fmt.Errorf("%T does not implement Fault", t.New())
+	}
+	if !embeds(reflect.TypeOf(f), reflect.TypeOf(parent.New())) {
+		return fmt.Errorf("%T does not embed %T", f, parent.New())
+	}
+	if f.GetKind() != t.Kind {
+		return fmt.Errorf("%T reports kind %q", f, f.GetKind())
+	}
+	if t.Abstract {
+		return nil
+	}
+	b, err := polymorphic.Marshal(f)
+	if err != nil {
+		return err
+	}
+	d := struct {
+		Kind string
+	}{}
+	err = polymorphic.Unmarshal(b, &d, false)
+	if err != nil {
+		return err
+	}
+	if d.Kind != t.Kind {
+		return fmt.Errorf("%T writes kind %q", f, d.Kind)
+	}
+	return nil
+}
+
+// embeds reports whether the struct pointed by t embeds the struct pointed by
+// base at any depth.
+func embeds(t reflect.Type, base reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || base.Kind() != reflect.Ptr {
+		return false
+	}
+	return embedsStruct(t.Elem(), base.Elem())
+}
+
+func embedsStruct(t reflect.Type, base reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		if field.Type == base || embedsStruct(field.Type, base) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalInto reads the indexed JSON into the fault struct. Extensions are
+// read with their own UnmarshalJSON as the unmarshal method promoted from the
+// embedded struct would drop their fields. In strict mode their members and
+// their causes are checked after that.
+func unmarshalInto(f Fault, v *polymorphic.Value, strict bool) error {
+	typ, _ := registry.Lookup(f.GetKind())
+	if !typ.Extension {
+		return f.(unmarshaler).unmarshalValue(v, strict)
+	}
+	err := checkConcrete(typ.Kind)
+	if err != nil {
+		return err
+	}
+	err = polymorphic.Unmarshal(v.Raw, f, strict)
+	if err != nil || !strict {
+		return err
+	}
+	return checkExtensionValue(f, v)
+}
+
+// checkExtensionValue fails on members the extension does not have and reads
+// the causes in strict mode. The UnmarshalJSON of the extension cannot know
+// that it is called in strict mode.
+func checkExtensionValue(f Fault, v *polymorphic.Value) error {
+	err := v.CheckMembers(f, "Kind", "SchemaVersion")
+	if err != nil {
+		return err
+	}
+	if cause := v.Member("Cause"); cause != nil {
+		_, err = unmarshalFaultValue(cause, true)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = unmarshalCauses(v.Member("Causes"), true)
+	return err
+}
@@ -0,0 +1,63 @@
+package raw_message
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// withRegistry runs test with a copy of the registry. The update function can
+// change the types while they are copied. Types registered by the test are
+// dropped with the copy.
+func withRegistry(test func(), update func(*polymorphic.Type)) {
+	r := polymorphic.NewRegistry()
+	for _, kind := range registry.Kinds() {
+		typ, _ := registry.Lookup(kind)
+		update(&typ)
+		r.Register(typ)
+	}
+	saved := registry
+	registry = r
+	defer func() { registry = saved }()
+	test()
+}
+
+func TestKindOf(t *testing.T) {
+	faults := []Fault{fault, runtimeFault, notFound}
+	for _, f := range faults {
+		if KindOf(f) != f.GetKind() {
+			t.Error("Kind mismatch", KindOf(f), f.GetKind())
+		}
+	}
+	if KindOf(notFound) != "NotFound" {
+		t.Error("Unexpected kind:", KindOf(notFound))
+	}
+	if KindOf(nil) != "" {
+		t.Error("Expected no kind for nil")
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	if parent, ok := Parent("NotFound"); !ok || parent != "RuntimeFault" {
+		t.Error("Unexpected parent of NotFound:", parent)
+	}
+	if _, ok := Parent("Fault"); ok {
+		t.Error("Expected Fault to be the root")
+	}
+	ancestors := Ancestors("NotFound")
+	if !reflect.DeepEqual(ancestors, []string{"RuntimeFault", "Fault"}) {
+		t.Error("Unexpected ancestors:", ancestors)
+	}
+	descendants := Descendants("Fault")
+	if !reflect.DeepEqual(descendants, []string{"RuntimeFault", "AlreadyExists", "Conflict",
+		"InvalidArgument", "NotFound", "PermissionDenied", "ResourceExhausted", "Timeout"}) {
+		t.Error("Unexpected descendants:", descendants)
+	}
+	if !IsA("NotFound", "Fault") || !IsA("RuntimeFault", "RuntimeFault") {
+		t.Error("Expected IsA to follow the hierarchy")
+	}
+	if IsA("Fault", "RuntimeFault") || IsA("Missing", "Fault") {
+		t.Error("Unexpected IsA relation")
+	}
+}
@@ -0,0 +1,163 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// InvalidArgument represents error when an argument of the request is not valid
+type InvalidArgument interface {
+	RuntimeFault
+	GetArgument() string
+	SetArgument(string)
+	// invalidArgument seals the interface. Types outside of this package
+	// implement it by embedding InvalidArgumentStruct.
+	invalidArgument()
+}
+
+// InvalidArgumentStruct contains the data about an argument that is not valid
+type InvalidArgumentStruct struct {
+	RuntimeFaultStruct
+	Argument string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "InvalidArgument",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &InvalidArgumentStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 400,
+			ExitCode:   64, // EX_USAGE
+		},
+	})
+}
+
+var _ InvalidArgument = &InvalidArgumentStruct{}
+var _ RuntimeFault = &InvalidArgumentStruct{}
+var _ Fault = &InvalidArgumentStruct{}
+var _ json.Marshaler = &InvalidArgumentStruct{}
+var _ json.Unmarshaler = &InvalidArgumentStruct{}
+var _ fmt.Formatter = &InvalidArgumentStruct{}
+
+// NewInvalidArgument creates an InvalidArgument for the named argument.
+// The options set the members inherited from FaultStruct like the message.
+func NewInvalidArgument(argument string, opts ...Option) *InvalidArgumentStruct {
+	x := &InvalidArgumentStruct{Argument: argument}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (ia *InvalidArgumentStruct) GetKind() string {
+	return "InvalidArgument"
+}
+
+// Error formats the fault with its kind, message, the argument that is not
+// valid and the cause
+func (ia *InvalidArgumentStruct) Error() string {
+	return formatError("InvalidArgument", ia.Message, ia.Argument, ia.Cause, ia.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (ia *InvalidArgumentStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, ia)
+}
+
+// invalidArgument is a marker to prevent converting struct with same fields
+// into InvalidArgument interface
+func (ia *InvalidArgumentStruct) invalidArgument() {
+}
+
+// GetArgument retrieves the name of the argument that is not valid
+func (ia *InvalidArgumentStruct) GetArgument() string {
+	return ia.Argument
+}
+
+// SetArgument sets the name of the argument that is not valid
+func (ia *InvalidArgumentStruct) SetArgument(argument string) {
+	ia.Argument = argument
+}
+
+// MarshalJSON writes InvalidArgument as JSON
+func (ia *InvalidArgumentStruct) MarshalJSON() ([]byte, error) {
+	return marshal(ia)
+}
+
+// encode writes the Kind first and then the members directly
+func (ia *InvalidArgumentStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "InvalidArgument")
+	if err != nil {
+		return err
+	}
+	err = ia.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Argument")
+	e.String(ia.Argument)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (ia *InvalidArgumentStruct) UnmarshalJSON(in []byte) error {
+	return unmarshal(ia, in, false)
+}
+
+func (ia *InvalidArgumentStruct) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		Argument      string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "InvalidArgument", strict)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	ia.Message = pxy.Message
+	ia.MessageKey = pxy.MessageKey
+	ia.MessageArgs = pxy.MessageArgs
+	ia.Cause = cause
+	ia.Causes = causes
+	ia.Argument = pxy.Argument
+	return nil
+}
+
+// UnmarshalInvalidArgument reads InvalidArgument or its subclasses from JSON
+// bytes
+func UnmarshalInvalidArgument(in []byte) (InvalidArgument, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if invalidArgument, ok := fault.(InvalidArgument); ok {
+		return invalidArgument, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal InvalidArgument from %v", fault)
+}
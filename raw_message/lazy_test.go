@@ -0,0 +1,135 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// lazyJSON has a member that the bindings do not know
+const lazyJSON = `{"Fault":{"Kind":"NotFound", "Message":"test message",` +
+	`"Cause":{"Kind":"RuntimeFault","Message":"inner message","Cause":null},` +
+	`"ObjKind":"VirtualMachine","Obj":"vm-42","Extra":"kept"}}`
+
+type lazyContainer struct {
+	Fault LazyFault
+}
+
+func TestLazyFaultForwards(t *testing.T) {
+	c := lazyContainer{}
+	err := json.Unmarshal([]byte(lazyJSON), &c)
+	if err != nil {
+		t.Error("Cannot deserialize lazy fault", err)
+		return
+	}
+	if c.Fault.GetKind() != "NotFound" || !c.Fault.IsA("RuntimeFault") || c.Fault.IsA("NotFoundChild") {
+		t.Error("Unexpected kind", c.Fault.GetKind())
+	}
+	if c.Fault.decoded {
+		t.Error("Expected the fault not to be decoded")
+	}
+	b, err := json.Marshal(&c)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	if !strings.Contains(string(b), `"Extra":"kept"`) {
+		t.Error("Expected the original bytes", string(b))
+	}
+
+	fault, err := c.Fault.Fault()
+	if err != nil {
+		t.Error("Cannot decode lazy fault", err)
+		return
+	}
+	validateNotFound(fault, t)
+	b, err = json.Marshal(&c)
+	if err != nil || !strings.Contains(string(b), `"Extra":"kept"`) {
+		t.Error("Expected the original bytes of the unmodified fault", string(b), err)
+	}
+}
+
+func TestLazyFaultModified(t *testing.T) {
+	c := lazyContainer{}
+	err := json.Unmarshal([]byte(lazyJSON), &c)
+	if err != nil {
+		t.Error("Cannot deserialize lazy fault", err)
+		return
+	}
+	var nf NotFound
+	if !c.Fault.As(&nf) {
+		t.Error("Expected a NotFound")
+		return
+	}
+	var f *FaultStruct
+	if c.Fault.As(&f) {
+		t.Error("Expected NotFound not to be a *FaultStruct")
+	}
+	nf.SetObj("vm-43")
+	b, err := json.Marshal(&c)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	s := string(b)
+	if !strings.Contains(s, `"Obj":"vm-43"`) || strings.Contains(s, "Extra") {
+		t.Error("Expected the modified fault", s)
+	}
+
+	c.Fault.Set(fault)
+	b, err = json.Marshal(&c)
+	if err != nil || c.Fault.GetKind() != "Fault" {
+		t.Error("Unexpected fault after Set", string(b), err)
+	}
+}
+
+func TestLazyFaultNull(t *testing.T) {
+	c := lazyContainer{}
+	err := json.Unmarshal([]byte(`{"Fault":null}`), &c)
+	if err != nil {
+		t.Error("Cannot deserialize lazy fault", err)
+		return
+	}
+	fault, err := c.Fault.Fault()
+	if fault != nil || err != nil || c.Fault.GetKind() != "" {
+		t.Error("Expected no fault", fault, err)
+	}
+	b, err := json.Marshal(&c)
+	if err != nil || string(b) != `{"Fault":null}` {
+		t.Error("Unexpected JSON", string(b), err)
+	}
+}
+
+func TestLazyFaultMarshalValue(t *testing.T) {
+	c := lazyContainer{}
+	err := json.Unmarshal([]byte(lazyJSON), &c)
+	if err != nil {
+		t.Error("Cannot deserialize lazy fault", err)
+		return
+	}
+	b, err := json.Marshal(c)
+	if err != nil || !strings.Contains(string(b), `"Extra":"kept"`) {
+		t.Error("Expected the original bytes when marshaled by value", string(b), err)
+	}
+	m := map[string]LazyFault{"fault": c.Fault}
+	b, err = json.Marshal(m)
+	if err != nil || !strings.Contains(string(b), `"Extra":"kept"`) {
+		t.Error("Expected the original bytes as map value", string(b), err)
+	}
+	decoded := map[string]LazyFault{}
+	err = json.Unmarshal(b, &decoded)
+	lf := decoded["fault"]
+	if err != nil || lf.GetKind() != "NotFound" {
+		t.Error("Cannot read the map back", err)
+	}
+
+	c.Fault.Set(fault)
+	b, err = json.Marshal(map[string]LazyFault{"fault": c.Fault})
+	if err != nil || !strings.Contains(string(b), `"Message":"test message"`) || strings.Contains(string(b), "Extra") {
+		t.Error("Expected the new fault as map value", string(b), err)
+	}
+	b, err = json.Marshal(lazyContainer{})
+	if err != nil || string(b) != `{"Fault":null}` {
+		t.Error("Expected a zero LazyFault to be null", string(b), err)
+	}
+}
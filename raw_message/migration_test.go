@@ -0,0 +1,134 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// legacyNotFound is the NotFound written before it was renamed from
+// ObjectNotFound with Type and Id members.
+const legacyNotFound = `{"Kind":"ObjectNotFound","Message":"test message",` +
+	`"Cause":{"Kind":"RuntimeFault","Message":"inner message"},` +
+	`"Type":"VirtualMachine","Id":"vm-42"}`
+
+// withMigrations runs test with a migrator that has only the given migrations
+func withMigrations(test func(), ms ...polymorphic.Migration) {
+	saved := migrations
+	migrations = polymorphic.NewMigrator("Kind", "SchemaVersion")
+	defer func() { migrations = saved }()
+	for _, m := range ms {
+		RegisterMigration(m)
+	}
+	test()
+}
+
+var renameNotFound = polymorphic.Migration{
+	Kind:    "ObjectNotFound",
+	NewKind: "NotFound",
+	Up: func(doc polymorphic.Document) error {
+		doc.Rename("Type", "ObjKind")
+		doc.Rename("Id", "Obj")
+		return nil
+	},
+	Down: func(doc polymorphic.Document) error {
+		doc.Rename("ObjKind", "Type")
+		doc.Rename("Obj", "Id")
+		return nil
+	},
+}
+
+func TestMigrationUpgrade(t *testing.T) {
+	withMigrations(func() {
+		fault, err := UnmarshalFaultStrict([]byte(legacyNotFound))
+		if err != nil {
+			t.Error("Cannot read legacy NotFound", err)
+			return
+		}
+		validateNotFound(fault, t)
+
+		b, err := json.Marshal(notFound)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			return
+		}
+		t.Log("JSON Bytes", string(b))
+		fault, err = UnmarshalFaultStrict(b)
+		if err != nil {
+			t.Error("Cannot read current NotFound", err)
+			return
+		}
+		validateNotFound(fault, t)
+	}, renameNotFound)
+}
+
+func TestMigrationDowngrade(t *testing.T) {
+	withMigrations(func() {
+		b, err := json.Marshal(notFound)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			return
+		}
+		b, err = DowngradeFault(b, 0)
+		if err != nil {
+			t.Error("Cannot downgrade NotFound", err)
+			return
+		}
+		t.Log("Downgraded JSON", string(b))
+		legacy := struct {
+			Kind          string
+			SchemaVersion *int
+			Type          string
+			Id            string
+		}{}
+		err = json.Unmarshal(b, &legacy)
+		if err != nil {
+			t.Error("Cannot read downgraded JSON", err)
+			return
+		}
+		if legacy.Kind != "ObjectNotFound" || legacy.SchemaVersion != nil ||
+			legacy.Type != "VirtualMachine" || legacy.Id != "vm-42" {
+			t.Error("Unexpected downgraded NotFound", string(b))
+		}
+		fault, err := UnmarshalFault(b)
+		if err != nil {
+			t.Error("Cannot read downgraded NotFound", err)
+			return
+		}
+		validateNotFound(fault, t)
+	}, renameNotFound)
+}
+
+func TestMigrationLazyFault(t *testing.T) {
+	withMigrations(func() {
+		lazy := &LazyFault{}
+		err := json.Unmarshal([]byte(legacyNotFound), lazy)
+		if err != nil {
+			t.Error("Cannot read legacy NotFound", err)
+			return
+		}
+		if lazy.GetKind() != "NotFound" {
+			t.Error("Expected the upgraded kind", lazy.GetKind())
+		}
+		fault, err := lazy.Fault()
+		if err != nil {
+			t.Error("Cannot decode legacy NotFound", err)
+			return
+		}
+		validateNotFound(fault, t)
+	}, renameNotFound)
+}
+
+func TestMigrationLowerCaseKind(t *testing.T) {
+	withMigrations(func() {
+		in := `{"kind":"ObjectNotFound","schemaVersion":0,"Message":"test message",` +
+			`"Cause":{"Kind":"RuntimeFault","Message":"inner message"},"type":"VirtualMachine","id":"vm-42"}`
+		fault, err := UnmarshalFaultStrict([]byte(in))
+		if err != nil {
+			t.Error("Cannot read legacy NotFound with lower case members", err)
+			return
+		}
+		validateNotFound(fault, t)
+	}, renameNotFound)
+}
@@ -3,6 +3,9 @@ package raw_message
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // NotFound represents error when object is not found
@@ -13,7 +16,9 @@ type NotFound interface {
 	SetObjKind(string)
 	GetObj() string
 	SetObj(string)
-	ZzNotFound()
+	// notFound seals the interface. Types outside of this package implement
+	// it by embedding NotFoundStruct.
+	notFound()
 }
 
 // NotFoundStruct contains the data about a not found error
@@ -23,15 +28,56 @@ type NotFoundStruct struct {
 	Obj     string
 }
 
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "NotFound",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &NotFoundStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 404,
+			ExitCode:   66, // EX_NOINPUT
+		},
+	})
+}
+
 var _ NotFound = &NotFoundStruct{}
 var _ RuntimeFault = &NotFoundStruct{}
 var _ Fault = &NotFoundStruct{}
 var _ json.Marshaler = &NotFoundStruct{}
 var _ json.Unmarshaler = &NotFoundStruct{}
+var _ fmt.Formatter = &NotFoundStruct{}
+
+// NewNotFound creates a NotFound for the object of objKind with id obj.
+// The options set the members inherited from FaultStruct like the message.
+func NewNotFound(objKind, obj string, opts ...Option) *NotFoundStruct {
+	x := &NotFoundStruct{ObjKind: objKind, Obj: obj}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (nfo *NotFoundStruct) GetKind() string {
+	return "NotFound"
+}
 
-// ZzNotFound is a marker to prevent converting struct with same fields into
+// Error formats the fault with its kind, message, the object that is not
+// found and the cause
+func (nfo *NotFoundStruct) Error() string {
+	details := strings.TrimSpace(nfo.ObjKind + " " + nfo.Obj)
+	return formatError("NotFound", nfo.Message, details, nfo.Cause, nfo.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (nfo *NotFoundStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, nfo)
+}
+
+// notFound is a marker to prevent converting struct with same fields into
 // NotFound interface
-func (nfo *NotFoundStruct) ZzNotFound() {
+func (nfo *NotFoundStruct) notFound() {
 }
 
 // GetObjKind retrieves the object kind of obj identifier
@@ -56,37 +102,68 @@ func (nfo *NotFoundStruct) SetObj(obj string) {
 
 // MarshalJSON writes a NotFoundObject as JSON
 func (nfo *NotFoundStruct) MarshalJSON() ([]byte, error) {
-	type marshalable NotFoundStruct
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "NotFound",
-		marshalable: marshalable(*nfo),
-	})
+	return marshal(nfo)
+}
+
+// encode writes the Kind first and then the members directly
+func (nfo *NotFoundStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "NotFound")
+	if err != nil {
+		return err
+	}
+	err = nfo.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(nfo.ObjKind)
+	e.Name("Obj")
+	e.String(nfo.Obj)
+	e.EndObject()
+	return nil
 }
 
 // UnmarshalJSON reads a fault from JSON
 func (nfo *NotFoundStruct) UnmarshalJSON(in []byte) error {
+	return unmarshal(nfo, in, false)
+}
+
+func (nfo *NotFoundStruct) unmarshalValue(v *polymorphic.Value, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   json.RawMessage
-		ObjKind string
-		Obj     string
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		ObjKind       string
+		Obj           string
 	}{}
-	err := json.Unmarshal(in, pxy)
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "NotFound", strict)
 	if err != nil {
 		return err
 	}
 	var cause Fault
 	if pxy.Cause != nil {
-		cause, err = UnmarshalFault(pxy.Cause)
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
 		if err != nil {
 			return err
 		}
 	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
 	nfo.Message = pxy.Message
+	nfo.MessageKey = pxy.MessageKey
+	nfo.MessageArgs = pxy.MessageArgs
 	nfo.Cause = cause
+	nfo.Causes = causes
 	nfo.Obj = pxy.Obj
 	nfo.ObjKind = pxy.ObjKind
 	return nil
@@ -101,5 +178,5 @@ func UnmarshalNotFound(in []byte) (NotFound, error) {
 	if notFound, ok := fault.(NotFound); ok {
 		return notFound, nil
 	}
-	return nil, fmt.Errorf("cannot unmarshal NotFound %v", fault)
+	return nil, fmt.Errorf("cannot unmarshal NotFound from %v", fault)
 }
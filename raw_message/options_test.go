@@ -0,0 +1,48 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+func TestConstructors(t *testing.T) {
+	nf := NewNotFound("VirtualMachine", "vm-42", WithMessage("test message"),
+		WithCause(NewRuntimeFault(WithMessage("inner message"))))
+	validateNotFound(nf, t)
+	b, err := json.Marshal(nf)
+	if err != nil || string(b) != notFoundJSON {
+		t.Error("Unexpected JSON", string(b), err)
+	}
+	faults := map[string]Fault{
+		"Fault":             NewFault(),
+		"RuntimeFault":      NewRuntimeFault(),
+		"InvalidArgument":   NewInvalidArgument("spec.memory"),
+		"AlreadyExists":     NewAlreadyExists("VirtualMachine", "vm-42"),
+		"PermissionDenied":  NewPermissionDenied("VirtualMachine.PowerOn"),
+		"Timeout":           NewTimeout("PowerOn"),
+		"Conflict":          NewConflict("VirtualMachine", "vm-42"),
+		"ResourceExhausted": NewResourceExhausted("memory"),
+	}
+	for kind, f := range faults {
+		if f.GetKind() != kind {
+			t.Error("Unexpected kind", f.GetKind(), kind)
+		}
+	}
+	if ia := NewInvalidArgument("spec.memory"); ia.Argument != "spec.memory" {
+		t.Error("Unexpected argument", ia.Argument)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	arg := polymorphic.MessageArg{Name: "vm", Value: "vm-42"}
+	f := NewConflict("VirtualMachine", "vm-42",
+		WithMessagef("%s was modified", "vm-42"),
+		WithMessageKey("vm.conflict", arg),
+		WithCauses(notFound, fault))
+	if f.Message != "vm-42 was modified" || f.MessageKey != "vm.conflict" || len(f.MessageArgs) != 1 ||
+		len(f.Causes) != 2 || f.Cause != nil {
+		t.Error("Unexpected fault", f)
+	}
+}
@@ -0,0 +1,163 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// PermissionDenied represents error when the caller is not allowed to perform
+// the operation
+type PermissionDenied interface {
+	RuntimeFault
+	GetPrivilege() string
+	SetPrivilege(string)
+	// permissionDenied seals the interface. Types outside of this package
+	// implement it by embedding PermissionDeniedStruct.
+	permissionDenied()
+}
+
+// PermissionDeniedStruct contains the data about a denied operation
+type PermissionDeniedStruct struct {
+	RuntimeFaultStruct
+	Privilege string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "PermissionDenied",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &PermissionDeniedStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			HTTPStatus: 403,
+			ExitCode:   77, // EX_NOPERM
+		},
+	})
+}
+
+var _ PermissionDenied = &PermissionDeniedStruct{}
+var _ RuntimeFault = &PermissionDeniedStruct{}
+var _ Fault = &PermissionDeniedStruct{}
+var _ json.Marshaler = &PermissionDeniedStruct{}
+var _ json.Unmarshaler = &PermissionDeniedStruct{}
+var _ fmt.Formatter = &PermissionDeniedStruct{}
+
+// NewPermissionDenied creates a PermissionDenied for the missing privilege.
+// The options set the members inherited from FaultStruct like the message.
+func NewPermissionDenied(privilege string, opts ...Option) *PermissionDeniedStruct {
+	x := &PermissionDeniedStruct{Privilege: privilege}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (pd *PermissionDeniedStruct) GetKind() string {
+	return "PermissionDenied"
+}
+
+// Error formats the fault with its kind, message, the missing privilege
+// and the cause
+func (pd *PermissionDeniedStruct) Error() string {
+	return formatError("PermissionDenied", pd.Message, pd.Privilege, pd.Cause, pd.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (pd *PermissionDeniedStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, pd)
+}
+
+// permissionDenied is a marker to prevent converting struct with same fields
+// into PermissionDenied interface
+func (pd *PermissionDeniedStruct) permissionDenied() {
+}
+
+// GetPrivilege retrieves the privilege the caller is missing
+func (pd *PermissionDeniedStruct) GetPrivilege() string {
+	return pd.Privilege
+}
+
+// SetPrivilege sets the privilege the caller is missing
+func (pd *PermissionDeniedStruct) SetPrivilege(privilege string) {
+	pd.Privilege = privilege
+}
+
+// MarshalJSON writes PermissionDenied as JSON
+func (pd *PermissionDeniedStruct) MarshalJSON() ([]byte, error) {
+	return marshal(pd)
+}
+
+// encode writes the Kind first and then the members directly
+func (pd *PermissionDeniedStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "PermissionDenied")
+	if err != nil {
+		return err
+	}
+	err = pd.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Privilege")
+	e.String(pd.Privilege)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (pd *PermissionDeniedStruct) UnmarshalJSON(in []byte) error {
+	return unmarshal(pd, in, false)
+}
+
+func (pd *PermissionDeniedStruct) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		Privilege     string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "PermissionDenied", strict)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	pd.Message = pxy.Message
+	pd.MessageKey = pxy.MessageKey
+	pd.MessageArgs = pxy.MessageArgs
+	pd.Cause = cause
+	pd.Causes = causes
+	pd.Privilege = pxy.Privilege
+	return nil
+}
+
+// UnmarshalPermissionDenied reads PermissionDenied or its subclasses from JSON
+// bytes
+func UnmarshalPermissionDenied(in []byte) (PermissionDenied, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if permissionDenied, ok := fault.(PermissionDenied); ok {
+		return permissionDenied, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal PermissionDenied from %v", fault)
+}
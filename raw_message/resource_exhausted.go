@@ -0,0 +1,161 @@
+package raw_message
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// ResourceExhausted represents error when a quota or the capacity of a resource
+// is exhausted
+type ResourceExhausted interface {
+	RuntimeFault
+	GetResource() string
+	SetResource(string)
+	// resourceExhausted seals the interface. Types outside of this package
+	// implement it by embedding ResourceExhaustedStruct.
+	resourceExhausted()
+}
+
+// ResourceExhaustedStruct contains the data about an exhausted resource
+type ResourceExhaustedStruct struct {
+	RuntimeFaultStruct
+	Resource string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "ResourceExhausted",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &ResourceExhaustedStruct{} },
+		Classification: polymorphic.Classification{
+			HTTPStatus: 429,
+		},
+	})
+}
+
+var _ ResourceExhausted = &ResourceExhaustedStruct{}
+var _ RuntimeFault = &ResourceExhaustedStruct{}
+var _ Fault = &ResourceExhaustedStruct{}
+var _ json.Marshaler = &ResourceExhaustedStruct{}
+var _ json.Unmarshaler = &ResourceExhaustedStruct{}
+var _ fmt.Formatter = &ResourceExhaustedStruct{}
+
+// NewResourceExhausted creates a ResourceExhausted for the exhausted resource.
+// The options set the members inherited from FaultStruct like the message.
+func NewResourceExhausted(resource string, opts ...Option) *ResourceExhaustedStruct {
+	x := &ResourceExhaustedStruct{Resource: resource}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (rex *ResourceExhaustedStruct) GetKind() string {
+	return "ResourceExhausted"
+}
+
+// Error formats the fault with its kind, message, the exhausted resource
+// and the cause
+func (rex *ResourceExhaustedStruct) Error() string {
+	return formatError("ResourceExhausted", rex.Message, rex.Resource, rex.Cause, rex.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (rex *ResourceExhaustedStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, rex)
+}
+
+// resourceExhausted is a marker to prevent converting struct with same fields
+// into ResourceExhausted interface
+func (rex *ResourceExhaustedStruct) resourceExhausted() {
+}
+
+// GetResource retrieves the quota or resource that is exhausted
+func (rex *ResourceExhaustedStruct) GetResource() string {
+	return rex.Resource
+}
+
+// SetResource sets the quota or resource that is exhausted
+func (rex *ResourceExhaustedStruct) SetResource(resource string) {
+	rex.Resource = resource
+}
+
+// MarshalJSON writes ResourceExhausted as JSON
+func (rex *ResourceExhaustedStruct) MarshalJSON() ([]byte, error) {
+	return marshal(rex)
+}
+
+// encode writes the Kind first and then the members directly
+func (rex *ResourceExhaustedStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "ResourceExhausted")
+	if err != nil {
+		return err
+	}
+	err = rex.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Resource")
+	e.String(rex.Resource)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (rex *ResourceExhaustedStruct) UnmarshalJSON(in []byte) error {
+	return unmarshal(rex, in, false)
+}
+
+func (rex *ResourceExhaustedStruct) unmarshalValue(v *polymorphic.Value, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
+		Resource      string
+	}{}
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "ResourceExhausted", strict)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
+		if err != nil {
+			return err
+		}
+	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
+	rex.Message = pxy.Message
+	rex.MessageKey = pxy.MessageKey
+	rex.MessageArgs = pxy.MessageArgs
+	rex.Cause = cause
+	rex.Causes = causes
+	rex.Resource = pxy.Resource
+	return nil
+}
+
+// UnmarshalResourceExhausted reads ResourceExhausted or its subclasses from
+// JSON bytes
+func UnmarshalResourceExhausted(in []byte) (ResourceExhausted, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if resourceExhausted, ok := fault.(ResourceExhausted); ok {
+		return resourceExhausted, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal ResourceExhausted from %v", fault)
+}
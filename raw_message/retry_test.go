@@ -0,0 +1,77 @@
+package raw_message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// fakeClock records the delays and does not wait
+type fakeClock struct {
+	delays []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+// retryCalls runs Retry with a function that always fails with err and
+// returns the number of calls and the delays
+func retryCalls(rules map[string]polymorphic.RetryRule, err error) (int, []time.Duration) {
+	clock := &fakeClock{}
+	calls := 0
+	policy := polymorphic.RetryPolicy{MaxAttempts: 3, Rules: rules, Clock: clock}
+	_ = Retry(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		return err
+	})
+	return calls, clock.delays
+}
+
+func TestRetryClassification(t *testing.T) {
+	timeout := fmt.Errorf("power on: %w", &TimeoutStruct{Operation: "PowerOn"})
+	calls, delays := retryCalls(nil, timeout)
+	if calls != 3 || fmt.Sprint(delays) != "[100ms 200ms]" {
+		t.Error("Expected Timeout to be retried", calls, delays)
+	}
+	for _, err := range []error{notFound, fmt.Errorf("get: %w", notFound), errors.New("plain")} {
+		if calls, _ := retryCalls(nil, err); calls != 1 {
+			t.Error("Expected no retries for", err, calls)
+		}
+	}
+	calls = 0
+	err := Retry(context.Background(), polymorphic.RetryPolicy{Clock: &fakeClock{}}, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return timeout
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Error("Expected the retry to succeed", err, calls)
+	}
+}
+
+func TestRetryRules(t *testing.T) {
+	rules := map[string]polymorphic.RetryRule{
+		"RuntimeFault": {Backoff: time.Second},
+		"NotFound":     {Retry: polymorphic.Retryable},
+		"Conflict":     {Retry: polymorphic.NotRetryable},
+	}
+	if calls, delays := retryCalls(rules, &TimeoutStruct{}); calls != 3 || fmt.Sprint(delays) != "[1s 2s]" {
+		t.Error("Expected the backoff of the RuntimeFault rule", calls, delays)
+	}
+	if calls, _ := retryCalls(rules, notFound); calls != 3 {
+		t.Error("Expected the rule to retry NotFound", calls)
+	}
+	if calls, _ := retryCalls(rules, &ConflictStruct{}); calls != 1 {
+		t.Error("Expected the rule to stop Conflict", calls)
+	}
+}
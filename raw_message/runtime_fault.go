@@ -3,14 +3,18 @@ package raw_message
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // RuntimeFault represents all runtime faults that can be thrown
 // To be generated
 type RuntimeFault interface {
 	Fault
-	// ZzRuntimeFault disallows converting Fault struct to RuntimeFault interface
-	ZzRuntimeFault()
+	// runtimeFault disallows converting Fault struct to RuntimeFault interface.
+	// It is unexported to seal the interface. Types outside of this package
+	// implement it by embedding RuntimeFaultStruct.
+	runtimeFault()
 }
 
 // RuntimeFaultStruct represents fault
@@ -18,47 +22,112 @@ type RuntimeFaultStruct struct {
 	FaultStruct
 }
 
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "RuntimeFault",
+		Parent: "Fault",
+		New:    func() interface{} { return &RuntimeFaultStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.Retryable,
+			HTTPStatus: 503,
+			ExitCode:   75, // EX_TEMPFAIL of sysexits.h
+		},
+	})
+}
+
 var _ Fault = &RuntimeFaultStruct{}
 var _ RuntimeFault = &RuntimeFaultStruct{}
 var _ json.Marshaler = &RuntimeFaultStruct{}
 var _ json.Unmarshaler = &RuntimeFaultStruct{}
+var _ fmt.Formatter = &RuntimeFaultStruct{}
+
+// NewRuntimeFault creates a RuntimeFault.
+// The options set the members inherited from FaultStruct like the message.
+func NewRuntimeFault(opts ...Option) *RuntimeFaultStruct {
+	x := &RuntimeFaultStruct{}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (rf *RuntimeFaultStruct) GetKind() string {
+	return "RuntimeFault"
+}
 
-// ZzRuntimeFault is a marker it prevents converting Fault struct to
+// Error formats the fault with its kind, message and cause
+func (rf *RuntimeFaultStruct) Error() string {
+	return formatError("RuntimeFault", rf.Message, "", rf.Cause, rf.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (rf *RuntimeFaultStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, rf)
+}
+
+// runtimeFault is a marker it prevents converting Fault struct to
 // RuntimeFault interface
-func (rf *RuntimeFaultStruct) ZzRuntimeFault() {
+func (rf *RuntimeFaultStruct) runtimeFault() {
 }
 
 // MarshalJSON writes a RuntimeFaultObject as JSON
 func (rf *RuntimeFaultStruct) MarshalJSON() ([]byte, error) {
-	type marshalable RuntimeFaultStruct
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "RuntimeFault",
-		marshalable: marshalable(*rf),
-	})
+	return marshal(rf)
+}
+
+// encode writes the Kind first and then the members directly
+func (rf *RuntimeFaultStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "RuntimeFault")
+	if err != nil {
+		return err
+	}
+	err = rf.FaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.EndObject()
+	return nil
 }
 
 // UnmarshalJSON reads a fault from JSON
 func (rf *RuntimeFaultStruct) UnmarshalJSON(in []byte) error {
+	return unmarshal(rf, in, false)
+}
+
+func (rf *RuntimeFaultStruct) unmarshalValue(v *polymorphic.Value, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   json.RawMessage
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         *polymorphic.Value
+		Causes        *polymorphic.Value
 	}{}
-	err := json.Unmarshal(in, pxy)
+	err := v.Decode(pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "RuntimeFault", strict)
 	if err != nil {
 		return err
 	}
 	var cause Fault
 	if pxy.Cause != nil {
-		cause, err = UnmarshalFault(pxy.Cause)
+		cause, err = unmarshalFaultValue(pxy.Cause, strict)
 		if err != nil {
 			return err
 		}
 	}
+	causes, err := unmarshalCauses(pxy.Causes, strict)
+	if err != nil {
+		return err
+	}
 	rf.Message = pxy.Message
+	rf.MessageKey = pxy.MessageKey
+	rf.MessageArgs = pxy.MessageArgs
 	rf.Cause = cause
+	rf.Causes = causes
 	return nil
 }
 
@@ -71,5 +140,5 @@ func UnmarshalRuntimeFault(in []byte) (RuntimeFault, error) {
 	if runtimeFault, ok := fault.(RuntimeFault); ok {
 		return runtimeFault, nil
 	}
-	return nil, fmt.Errorf("cannot unmarshal RuntimeFault %v", fault)
+	return nil, fmt.Errorf("cannot unmarshal RuntimeFault from %v", fault)
 }
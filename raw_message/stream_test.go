@@ -0,0 +1,61 @@
+package raw_message
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+func TestFaultStream(t *testing.T) {
+	b, err := json.Marshal([]Fault{fault, runtimeFault, notFound})
+	if err != nil {
+		t.Error("Array serialization failed", err)
+		return
+	}
+	var ndjson bytes.Buffer
+	enc := json.NewEncoder(&ndjson)
+	for _, f := range []Fault{fault, runtimeFault, notFound} {
+		err = enc.Encode(f)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			return
+		}
+	}
+	for _, in := range [][]byte{b, ndjson.Bytes()} {
+		s := NewFaultStream(bytes.NewReader(in))
+		var faults []Fault
+		for s.Next(context.Background()) {
+			faults = append(faults, s.Fault())
+		}
+		if s.Err() != nil {
+			t.Error("Cannot read faults", s.Err())
+			continue
+		}
+		if len(faults) != 3 {
+			t.Error("Expected 3 faults but encountered", len(faults))
+			continue
+		}
+		validateFault(faults[0], t)
+		validateRuntimeFault(faults[1], t)
+		validateNotFound(faults[2], t)
+	}
+}
+
+func TestFaultStreamError(t *testing.T) {
+	in := `[{"Kind":"Fault"},{"Kind":"RuntimeFault","Message":1}]`
+	s := NewFaultStream(strings.NewReader(in))
+	count := 0
+	for s.Next(context.Background()) {
+		count++
+	}
+	var elementErr *polymorphic.ElementError
+	if !errors.As(s.Err(), &elementErr) || elementErr.Index != 1 || count != 1 {
+		t.Error("Expected to fail on element 1", s.Err(), count)
+	}
+	t.Log("Stream error", s.Err())
+}
@@ -0,0 +1,48 @@
+package raw_message
+
+import "github.com/karaatanassov/go_polymorphic_json/polymorphic"
+
+// unmarshaler is implemented by all fault structs. They read the JSON indexed
+// by polymorphic.Parse so nested causes are not scanned again and the strict
+// flag reaches the nested Cause fields.
+type unmarshaler interface {
+	unmarshalValue(v *polymorphic.Value, strict bool) error
+}
+
+var _ unmarshaler = &FaultStruct{}
+var _ unmarshaler = &RuntimeFaultStruct{}
+var _ unmarshaler = &NotFoundStruct{}
+var _ unmarshaler = &InvalidArgumentStruct{}
+var _ unmarshaler = &AlreadyExistsStruct{}
+var _ unmarshaler = &PermissionDeniedStruct{}
+var _ unmarshaler = &TimeoutStruct{}
+var _ unmarshaler = &ConflictStruct{}
+var _ unmarshaler = &ResourceExhaustedStruct{}
+
+// UnmarshalFaultStrict reads a fault from JSON like UnmarshalFault. It fails
+// on members that are not known to the kind and on unknown Kind values instead
+// of ignoring them. Nested causes are read in strict mode too.
+func UnmarshalFaultStrict(in []byte) (Fault, error) {
+	return unmarshalFault(in, true)
+}
+
+// UnmarshalStrict reads JSON into the fault struct v. It fails on unknown
+// members and when the Kind in the payload is neither the type of v nor one of
+// its descendants. Extensions registered from other packages are read with
+// their own UnmarshalJSON.
+func UnmarshalStrict(in []byte, v Fault) error {
+	value, err := polymorphic.Parse(in)
+	if err != nil {
+		return err
+	}
+	return unmarshalInto(v, value, true)
+}
+
+// unmarshal indexes the JSON and reads it into the fault struct
+func unmarshal(u unmarshaler, in []byte, strict bool) error {
+	v, err := polymorphic.Parse(in)
+	if err != nil {
+		return err
+	}
+	return u.unmarshalValue(v, strict)
+}
@@ -0,0 +1,163 @@
+package type_meta
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+var cause = &FaultStruct{
+	Message: "inner message",
+}
+
+var notFoundV1 = &NotFoundStruct{
+	FaultStruct: FaultStruct{
+		Message: "test message",
+		Cause:   cause,
+	},
+	ObjKind: "VirtualMachine",
+	Obj:     "vm-42",
+}
+
+var notFoundV2 = &NotFoundV2Struct{
+	NotFoundStruct: *notFoundV1,
+	ObjNamespace:   "production",
+}
+
+func TestMarshalDiscriminators(t *testing.T) {
+	b, err := json.Marshal(notFoundV2)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+
+	s := string(b)
+	t.Log("JSON Bytes", s)
+
+	if !strings.HasPrefix(s, `{"apiVersion":"faults/v2","kind":"NotFound",`) {
+		t.Error("Expected both discriminators first", s)
+	}
+}
+
+func TestUnmarshalVersions(t *testing.T) {
+	for _, f := range []Fault{notFoundV1, notFoundV2} {
+		b, err := json.Marshal(f)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			return
+		}
+		res, err := UnmarshalNotFound(b)
+		if err != nil {
+			t.Error("Cannot deserialize fault", err)
+			return
+		}
+		key, _ := KeyOf(res)
+		if key.Version != f.GetAPIVersion() || key.Kind != "NotFound" {
+			t.Error("Unexpected key", key)
+		}
+		validateNotFound(res, t)
+	}
+}
+
+func TestUnknownVersion(t *testing.T) {
+	payloads := []string{
+		`{"apiVersion":"faults/v3","kind":"NotFound"}`,
+		`{"kind":"NotFound"}`,
+		`{"apiVersion":"faults/v2","kind":"Fault"}`,
+	}
+	for _, p := range payloads {
+		_, err := UnmarshalFault([]byte(p))
+		if err == nil {
+			t.Error("Expected to fail reading", p)
+		}
+		t.Log("Unmarshal error", err)
+	}
+}
+
+func TestMixedVersionList(t *testing.T) {
+	list := &FaultList{
+		Items: []Fault{cause, notFoundV1, notFoundV2},
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+
+	t.Log("JSON Bytes", string(b))
+
+	res := FaultList{}
+	err = json.Unmarshal(b, &res)
+	if err != nil {
+		t.Error("Cannot deserialize list", err)
+		return
+	}
+	if len(res.Items) != 3 {
+		t.Error("Expected 3 items but encountered", len(res.Items))
+		return
+	}
+	if _, ok := res.Items[0].(*FaultStruct); !ok {
+		t.Error("Unexpected type", res.Items[0])
+	}
+	if _, ok := res.Items[1].(*NotFoundStruct); !ok {
+		t.Error("Unexpected type", res.Items[1])
+	}
+	v2, ok := res.Items[2].(*NotFoundV2Struct)
+	if !ok {
+		t.Error("Unexpected type", res.Items[2])
+		return
+	}
+	if v2.ObjNamespace != "production" {
+		t.Error("Unexpected namespace:", v2.ObjNamespace)
+	}
+	validateNotFound(v2, t)
+}
+
+func validateNotFound(fault Fault, t *testing.T) {
+	if fault.GetMessage() != "test message" {
+		t.Error("Unexpected message:", fault.GetMessage())
+	}
+	if notFound, ok := fault.(NotFound); ok {
+		if notFound.GetObjKind() != "VirtualMachine" {
+			t.Error("Unexpected obj kind:", notFound.GetObjKind())
+		}
+		if notFound.GetObj() != "vm-42" {
+			t.Error("Unexpected obj:", notFound.GetObj())
+		}
+	} else {
+		t.Error("Unexpected type", fault)
+	}
+	inner, ok := fault.GetCause().(*FaultStruct)
+	if !ok || inner.Message != "inner message" {
+		t.Error("Unexpected cause", fault.GetCause())
+	}
+}
+
+func TestUnmarshalNotFoundNull(t *testing.T) {
+	nf, err := UnmarshalNotFound([]byte("null"))
+	if nf != nil || err == nil || err.Error() != "cannot unmarshal NotFound from null" {
+		t.Error("Expected null to fail", nf, err)
+	}
+	_, err = UnmarshalNotFound([]byte(`{"apiVersion":"faults/v1","kind":"Fault"}`))
+	if err == nil || err.Error() != "cannot unmarshal NotFound from Fault" {
+		t.Error("Expected Fault to fail", err)
+	}
+}
+
+func TestVersionedHierarchy(t *testing.T) {
+	v2 := polymorphic.Key{Version: "faults/v2", Kind: "NotFound"}
+	v1 := polymorphic.Key{Version: "faults/v1", Kind: "NotFound"}
+	fault := polymorphic.Key{Version: "faults/v1", Kind: "Fault"}
+	if !IsA(v2, v1) || IsA(v1, v2) {
+		t.Error("Expected the v2 NotFound to extend the v1 NotFound")
+	}
+	if !IsA(v2, fault) || IsA(fault, v2) {
+		t.Error("Expected the v2 NotFound to extend Fault")
+	}
+	ancestors := Ancestors(v2)
+	if len(ancestors) != 2 || ancestors[0] != v1 || ancestors[1] != fault {
+		t.Error("Unexpected ancestors", ancestors)
+	}
+}
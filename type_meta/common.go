@@ -0,0 +1,117 @@
+package type_meta
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+var (
+	// registry holds the fault types keyed by apiVersion and kind
+	registry = polymorphic.NewRegistry()
+	// discriminator names the properties that select the fault type
+	discriminator = polymorphic.Discriminator{
+		VersionProperty: "apiVersion",
+		KindProperty:    "kind",
+	}
+)
+
+// KeyOf returns the apiVersion and kind of the fault. The result is false if
+// the type of the fault is not registered.
+func KeyOf(v Fault) (polymorphic.Key, bool) {
+	return registry.KeyOf(v)
+}
+
+// IsA reports whether the type of key is the type of ancestor or one of its
+// descendants
+func IsA(key, ancestor polymorphic.Key) bool {
+	return registry.IsAKey(key, ancestor)
+}
+
+// Ancestors lists the keys of the types that the type of key extends starting
+// with its parent
+func Ancestors(key polymorphic.Key) []polymorphic.Key {
+	return registry.AncestorsKey(key)
+}
+
+// UnmarshalFault reads a fault from JSON and instantiates the type registered
+// for the apiVersion and kind of the object. It fails if the pair is not
+// registered as the same kind has a different shape in each version.
+func UnmarshalFault(in []byte) (Fault, error) {
+	key, ok, err := discriminator.Scan(in)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	res, ok := registry.NewKey(key).(Fault)
+	if !ok {
+		return nil, fmt.Errorf("unknown apiVersion %q and kind %q", key.Version, key.Kind)
+	}
+	err = polymorphic.Unmarshal(in, res, false)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UnmarshalFaults reads a JSON array of faults of mixed versions. Each element
+// is instantiated with the type registered for its apiVersion and kind.
+func UnmarshalFaults(in []byte) ([]Fault, error) {
+	var raw []json.RawMessage
+	err := polymorphic.Unmarshal(in, &raw, false)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	faults := make([]Fault, 0, len(raw))
+	for i, rawFault := range raw {
+		fault, err := UnmarshalFault(rawFault)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %v", i, err)
+		}
+		faults = append(faults, fault)
+	}
+	return faults, nil
+}
+
+// FaultList is a list of faults of mixed versions like the Kubernetes List
+type FaultList struct {
+	Items []Fault `json:"items"`
+}
+
+var _ json.Marshaler = &FaultList{}
+var _ json.Unmarshaler = &FaultList{}
+
+// MarshalJSON writes the list with its own apiVersion and kind
+func (l *FaultList) MarshalJSON() ([]byte, error) {
+	return polymorphic.Marshal(struct {
+		APIVersion string  `json:"apiVersion"`
+		Kind       string  `json:"kind"`
+		Items      []Fault `json:"items"`
+	}{
+		APIVersion: "v1",
+		Kind:       "List",
+		Items:      l.Items,
+	})
+}
+
+// UnmarshalJSON reads the list items into their versioned types
+func (l *FaultList) UnmarshalJSON(in []byte) error {
+	pxy := &struct {
+		Items json.RawMessage `json:"items"`
+	}{}
+	err := polymorphic.Unmarshal(in, pxy, false)
+	if err != nil {
+		return err
+	}
+	l.Items = nil
+	if pxy.Items != nil {
+		l.Items, err = UnmarshalFaults(pxy.Items)
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+// Code to be generated for a base type.
+
+// Package type_meta shows faults that are discriminated by two properties
+// like the Kubernetes TypeMeta - apiVersion and kind. The same kind can have
+// different shape in each API version.
+package type_meta
+
+import (
+	"encoding/json"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// Fault represents a base error in any API version
+type Fault interface {
+	// fault seals the interface
+	fault()
+	GetAPIVersion() string
+	GetKind() string
+	GetMessage() string
+	SetMessage(string)
+	GetCause() Fault
+	SetCause(Fault)
+}
+
+// FaultStruct contains information about a base fault in faults/v1
+type FaultStruct struct {
+	Message string `json:"message"`
+	Cause   Fault  `json:"cause,omitempty"`
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Version: "faults/v1",
+		Kind:    "Fault",
+		New:     func() interface{} { return &FaultStruct{} },
+	})
+}
+
+var _ Fault = &FaultStruct{}
+var _ json.Marshaler = &FaultStruct{}
+var _ json.Unmarshaler = &FaultStruct{}
+
+// fault is a marker that seals the Fault interface
+func (fault *FaultStruct) fault() {
+}
+
+// GetAPIVersion returns the version discriminator of the fault
+func (fault *FaultStruct) GetAPIVersion() string {
+	return "faults/v1"
+}
+
+// GetKind returns the kind discriminator of the fault
+func (fault *FaultStruct) GetKind() string {
+	return "Fault"
+}
+
+// GetMessage retrieves the message value
+func (fault *FaultStruct) GetMessage() string {
+	return fault.Message
+}
+
+// SetMessage updates the message value
+func (fault *FaultStruct) SetMessage(message string) {
+	fault.Message = message
+}
+
+// GetCause returns the case of fault
+func (fault *FaultStruct) GetCause() Fault {
+	return fault.Cause
+}
+
+// SetCause sets the cause of the fault
+func (fault *FaultStruct) SetCause(cause Fault) {
+	fault.Cause = cause
+}
+
+// UnmarshalJSON reads a fault from JSON
+func (fault *FaultStruct) UnmarshalJSON(in []byte) error {
+	pxy := &struct {
+		Message string          `json:"message"`
+		Cause   json.RawMessage `json:"cause"`
+	}{}
+	err := polymorphic.Unmarshal(in, pxy, false)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = UnmarshalFault(pxy.Cause)
+		if err != nil {
+			return err
+		}
+	}
+	fault.Message = pxy.Message
+	fault.Cause = cause
+	return nil
+}
+
+// MarshalJSON writes Fault as JSON and adds both discriminators
+func (fault *FaultStruct) MarshalJSON() ([]byte, error) {
+	type marshalable FaultStruct
+	return polymorphic.Marshal(struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		marshalable
+	}{
+		APIVersion:  "faults/v1",
+		Kind:        "Fault",
+		marshalable: marshalable(*fault),
+	})
+}
@@ -0,0 +1,207 @@
+package type_meta
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// NotFound represents error when object is not found in any API version
+type NotFound interface {
+	Fault
+	GetObjKind() string
+	SetObjKind(string)
+	GetObj() string
+	SetObj(string)
+	// notFound seals the interface
+	notFound()
+}
+
+// NotFoundStruct contains the data about a not found error in faults/v1
+type NotFoundStruct struct {
+	FaultStruct
+	ObjKind string `json:"objKind"`
+	Obj     string `json:"obj"`
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Version: "faults/v1",
+		Kind:    "NotFound",
+		Parent:  "Fault",
+		New:     func() interface{} { return &NotFoundStruct{} },
+	})
+}
+
+var _ NotFound = &NotFoundStruct{}
+var _ json.Marshaler = &NotFoundStruct{}
+var _ json.Unmarshaler = &NotFoundStruct{}
+
+// notFound is a marker that seals the NotFound interface
+func (nfo *NotFoundStruct) notFound() {
+}
+
+// GetAPIVersion returns the version discriminator of the fault
+func (nfo *NotFoundStruct) GetAPIVersion() string {
+	return "faults/v1"
+}
+
+// GetKind returns the kind discriminator of the fault
+func (nfo *NotFoundStruct) GetKind() string {
+	return "NotFound"
+}
+
+// GetObjKind retrieves the object kind of obj identifier
+func (nfo *NotFoundStruct) GetObjKind() string {
+	return nfo.ObjKind
+}
+
+// SetObjKind sets the kind of object references by obj
+func (nfo *NotFoundStruct) SetObjKind(objKind string) {
+	nfo.ObjKind = objKind
+}
+
+// GetObj retrieves the obj value
+func (nfo *NotFoundStruct) GetObj() string {
+	return nfo.Obj
+}
+
+// SetObj sets the obj id value
+func (nfo *NotFoundStruct) SetObj(obj string) {
+	nfo.Obj = obj
+}
+
+// MarshalJSON writes a NotFound as JSON with both discriminators
+func (nfo *NotFoundStruct) MarshalJSON() ([]byte, error) {
+	type marshalable NotFoundStruct
+	return polymorphic.Marshal(struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		marshalable
+	}{
+		APIVersion:  "faults/v1",
+		Kind:        "NotFound",
+		marshalable: marshalable(*nfo),
+	})
+}
+
+// UnmarshalJSON reads a NotFound from JSON
+func (nfo *NotFoundStruct) UnmarshalJSON(in []byte) error {
+	pxy := &struct {
+		Message string          `json:"message"`
+		Cause   json.RawMessage `json:"cause"`
+		ObjKind string          `json:"objKind"`
+		Obj     string          `json:"obj"`
+	}{}
+	err := polymorphic.Unmarshal(in, pxy, false)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = UnmarshalFault(pxy.Cause)
+		if err != nil {
+			return err
+		}
+	}
+	nfo.Message = pxy.Message
+	nfo.Cause = cause
+	nfo.ObjKind = pxy.ObjKind
+	nfo.Obj = pxy.Obj
+	return nil
+}
+
+// NotFoundV2Struct contains the data about a not found error in faults/v2.
+// The object identifier is split into namespace and name.
+type NotFoundV2Struct struct {
+	NotFoundStruct
+	ObjNamespace string `json:"objNamespace"`
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Version: "faults/v2",
+		Kind:    "NotFound",
+		// The v2 NotFound extends the v1 NotFound it embeds
+		Parent:        "NotFound",
+		ParentVersion: "faults/v1",
+		New:           func() interface{} { return &NotFoundV2Struct{} },
+	})
+}
+
+var _ NotFound = &NotFoundV2Struct{}
+var _ json.Marshaler = &NotFoundV2Struct{}
+var _ json.Unmarshaler = &NotFoundV2Struct{}
+
+// GetAPIVersion returns the version discriminator of the fault
+func (nfo *NotFoundV2Struct) GetAPIVersion() string {
+	return "faults/v2"
+}
+
+// GetObjNamespace retrieves the namespace of obj
+func (nfo *NotFoundV2Struct) GetObjNamespace() string {
+	return nfo.ObjNamespace
+}
+
+// SetObjNamespace sets the namespace of obj
+func (nfo *NotFoundV2Struct) SetObjNamespace(objNamespace string) {
+	nfo.ObjNamespace = objNamespace
+}
+
+// MarshalJSON writes a NotFound as JSON with both discriminators
+func (nfo *NotFoundV2Struct) MarshalJSON() ([]byte, error) {
+	type marshalable NotFoundV2Struct
+	return polymorphic.Marshal(struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		marshalable
+	}{
+		APIVersion:  "faults/v2",
+		Kind:        "NotFound",
+		marshalable: marshalable(*nfo),
+	})
+}
+
+// UnmarshalJSON reads a NotFound from JSON
+func (nfo *NotFoundV2Struct) UnmarshalJSON(in []byte) error {
+	pxy := &struct {
+		Message      string          `json:"message"`
+		Cause        json.RawMessage `json:"cause"`
+		ObjKind      string          `json:"objKind"`
+		Obj          string          `json:"obj"`
+		ObjNamespace string          `json:"objNamespace"`
+	}{}
+	err := polymorphic.Unmarshal(in, pxy, false)
+	if err != nil {
+		return err
+	}
+	var cause Fault
+	if pxy.Cause != nil {
+		cause, err = UnmarshalFault(pxy.Cause)
+		if err != nil {
+			return err
+		}
+	}
+	nfo.Message = pxy.Message
+	nfo.Cause = cause
+	nfo.ObjKind = pxy.ObjKind
+	nfo.Obj = pxy.Obj
+	nfo.ObjNamespace = pxy.ObjNamespace
+	return nil
+}
+
+// UnmarshalNotFound reads NotFound of any version from JSON bytes
+func UnmarshalNotFound(in []byte) (NotFound, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if notFound, ok := fault.(NotFound); ok {
+		return notFound, nil
+	}
+	if fault == nil {
+		return nil, fmt.Errorf("cannot unmarshal NotFound from null")
+	}
+	return nil, fmt.Errorf("cannot unmarshal NotFound from %s", fault.GetKind())
+}
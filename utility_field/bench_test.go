@@ -0,0 +1,100 @@
+package utility_field
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// baselineField is FaultField of the baseline
+type baselineField struct {
+	Fault
+}
+
+// UnmarshalJSON reads the fault with baselineUnmarshalFault
+func (ff *baselineField) UnmarshalJSON(in []byte) error {
+	var err error
+	ff.Fault, err = baselineUnmarshalFault(in)
+	return err
+}
+
+// baselineUnmarshalFault is UnmarshalFault of the baseline before the
+// discriminator scan. It decodes the value once to find the Kind and once more
+// into the proxy of the kind. The Cause is read by the
+// baselineField the same way.
+func baselineUnmarshalFault(in []byte) (Fault, error) {
+	d := &struct {
+		Kind string
+	}{}
+	// Double pointer detects null values
+	err := json.Unmarshal(in, &d)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, nil
+	}
+	// The proxy of NotFound. The proxies of the other kinds lack ObjKind and
+	// Obj.
+	pxy := &struct {
+		Message string
+		Cause   baselineField
+		ObjKind string
+		Obj     string
+	}{}
+	err = json.Unmarshal(in, pxy)
+	if err != nil {
+		return nil, err
+	}
+	cause := pxy.Cause.Fault
+	switch d.Kind {
+	case "NotFound":
+		nf := &NotFoundStruct{ObjKind: pxy.ObjKind, Obj: pxy.Obj}
+		nf.Message = pxy.Message
+		nf.Cause = cause
+		return nf, nil
+	case "RuntimeFault":
+		rf := &RuntimeFaultStruct{}
+		rf.Message = pxy.Message
+		rf.Cause = cause
+		return rf, nil
+	}
+	return &FaultStruct{Message: pxy.Message, Cause: cause}, nil
+}
+
+func TestBaselineUnmarshalFault(t *testing.T) {
+	b, err := json.Marshal(notFound)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	fault, err := baselineUnmarshalFault(b)
+	if err != nil {
+		t.Error("Cannot deserialize fault", err)
+		return
+	}
+	validateNotFound(fault, t)
+}
+
+func benchmarkUnmarshal(b *testing.B, unmarshal func([]byte) (Fault, error)) {
+	in, err := json.Marshal(notFound)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(in)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = unmarshal(in)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalFault(b *testing.B) {
+	benchmarkUnmarshal(b, UnmarshalFault)
+}
+
+func BenchmarkBaselineUnmarshalFault(b *testing.B) {
+	benchmarkUnmarshal(b, baselineUnmarshalFault)
+}
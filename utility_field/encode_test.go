@@ -0,0 +1,105 @@
+package utility_field
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+const notFoundJSON = `{"Kind":"NotFound","Message":"test message",` +
+	`"Cause":{"Kind":"RuntimeFault","Message":"inner message","Cause":null},` +
+	`"ObjKind":"VirtualMachine","Obj":"vm-42"}`
+
+// copyMarshalNotFound is MarshalJSON as it was before the encoder. It copies
+// the fault into a struct that adds the discriminator.
+func copyMarshalNotFound(nfo *NotFoundStruct) ([]byte, error) {
+	type marshalable NotFoundStruct
+	return json.Marshal(struct {
+		Kind          string
+		SchemaVersion int `json:",omitempty"`
+		marshalable
+	}{
+		Kind:        "NotFound",
+		marshalable: marshalable(*nfo),
+	})
+}
+
+func TestEncodeFault(t *testing.T) {
+	var buf bytes.Buffer
+	err := EncodeFault(&buf, notFound)
+	if err != nil {
+		t.Error("Cannot encode fault", err)
+		return
+	}
+	if buf.String() != notFoundJSON {
+		t.Error("Unexpected JSON", buf.String())
+	}
+	b, err := json.Marshal(notFound)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	if string(b) != notFoundJSON {
+		t.Error("Unexpected MarshalJSON", string(b))
+	}
+	b, err = copyMarshalNotFound(notFound)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	if string(b) != notFoundJSON {
+		t.Error("Encoder differs from the struct copy", string(b))
+	}
+}
+
+func TestEncodeControlCharacters(t *testing.T) {
+	nf := NewNotFound("Virtual\bMachine", "vm\f42", WithMessage("a\b\f\x00\x1f\t<&>\u2028z"))
+	b, err := json.Marshal(nf)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	expected, err := copyMarshalNotFound(nf)
+	if err != nil || !bytes.Equal(b, expected) {
+		t.Error("Encoder differs from the struct copy", string(b), string(expected), err)
+	}
+}
+
+func TestEncodeNilFault(t *testing.T) {
+	var buf bytes.Buffer
+	err := EncodeFault(&buf, nil)
+	if err != nil || buf.String() != "null" {
+		t.Error("Unexpected encoding of nil", buf.String(), err)
+	}
+}
+
+func BenchmarkCopyMarshalFault(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := copyMarshalNotFound(notFound)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalFault(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := json.Marshal(notFound)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeFault(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := EncodeFault(ioutil.Discard, notFound)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package utility_field
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFaultError(t *testing.T) {
+	expected := map[error]string{
+		fault:          "Fault: test message: RuntimeFault: inner message",
+		runtimeFault:   "RuntimeFault: test message: RuntimeFault: inner message",
+		notFound:       "NotFound: test message (VirtualMachine vm-42): RuntimeFault: inner message",
+		&FaultStruct{}: "Fault",
+	}
+	for err, s := range expected {
+		if err.Error() != s {
+			t.Error("Unexpected error string", err.Error())
+		}
+	}
+}
+
+func TestFaultUnwrap(t *testing.T) {
+	err := fmt.Errorf("cannot get vm: %w", notFound)
+	var nf NotFound
+	if !errors.As(err, &nf) || nf != notFound {
+		t.Error("Expected to find the NotFound", err)
+	}
+	var rf RuntimeFault
+	if !errors.As(err, &rf) || rf != notFound {
+		t.Error("Expected NotFound to be a RuntimeFault", err)
+	}
+	if !errors.Is(err, &innerRuntimeFault) {
+		t.Error("Expected the cause in the chain", err)
+	}
+	if errors.As(fault, &nf) {
+		t.Error("Unexpected NotFound in", fault)
+	}
+	if errors.Unwrap(&innerRuntimeFault) != nil {
+		t.Error("Expected the chain to end without cause")
+	}
+	withNilCause := &FaultStruct{Message: "m", Cause: (*FaultStruct)(nil)}
+	if errors.Unwrap(withNilCause) != nil || withNilCause.Error() != "Fault: m" {
+		t.Error("Expected a nil pointer cause to end the chain", withNilCause.Error())
+	}
+}
+
+// joinError wraps several errors like the result of errors.Join
+type joinError []error
+
+func (e joinError) Error() string {
+	var messages []string
+	for _, err := range e {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e joinError) Unwrap() []error {
+	return e
+}
+
+// messages lists the messages of the cause chain
+func messages(f Fault) []string {
+	var res []string
+	for ; f != nil; f = FromError(errors.Unwrap(f)) {
+		res = append(res, f.GetKind()+" "+f.GetMessage())
+	}
+	return res
+}
+
+func TestFromError(t *testing.T) {
+	if FromError(nil) != nil {
+		t.Error("Expected nil for nil")
+	}
+	f := FromError(fmt.Errorf("read config: %w", fmt.Errorf("open file: %w", io.EOF)))
+	expected := []string{"Fault read config", "Fault open file", "Fault EOF"}
+	if fmt.Sprint(messages(f)) != fmt.Sprint(expected) {
+		t.Error("Unexpected chain", messages(f))
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	t.Log("JSON Bytes", string(b))
+
+	f = FromError(fmt.Errorf("get vm: %w", notFound))
+	if cause := errors.Unwrap(f); cause != notFound {
+		t.Error("Expected the fault to be kept", cause)
+	}
+	if FromError(notFound) != notFound {
+		t.Error("Expected the fault to be returned as is")
+	}
+}
+
+func TestFromJoinedError(t *testing.T) {
+	joined := joinError{io.EOF, nil, fmt.Errorf("lookup: %w", notFound)}
+	f := FromError(fmt.Errorf("batch: %w", joined))
+	expected := []string{"Fault batch", "Fault "}
+	if fmt.Sprint(messages(f)) != fmt.Sprint(expected) {
+		t.Error("Unexpected chain", messages(f))
+	}
+	causes := errors.Unwrap(f).(Fault).GetCauses()
+	if len(causes) != 2 {
+		t.Error("Expected a cause per joined error", causes)
+		return
+	}
+	expected = []string{"Fault lookup", "NotFound test message", "RuntimeFault inner message"}
+	if messages(causes[0])[0] != "Fault EOF" || fmt.Sprint(messages(causes[1])) != fmt.Sprint(expected) {
+		t.Error("Unexpected causes", messages(causes[0]), messages(causes[1]))
+	}
+	if !strings.HasPrefix(f.Error(), "Fault: batch: Fault [Fault: EOF; Fault: lookup: NotFound") {
+		t.Error("Expected the joined messages only in the causes", f.Error())
+	}
+	var nf NotFound
+	if !errors.As(f, &nf) || nf != notFound {
+		t.Error("Expected the NotFound in the causes")
+	}
+	if !errors.Is(f, &innerRuntimeFault) {
+		t.Error("Expected the cause of the NotFound in the tree")
+	}
+}
+
+// summaryError wraps several errors with a message of its own
+type summaryError struct {
+	joinError
+}
+
+func (e summaryError) Error() string {
+	return "2 lookups failed"
+}
+
+func TestFromJoinedErrorMessage(t *testing.T) {
+	nf := NewNotFound("Cat", "Lucie", WithMessage("The cat Lucie is missing"))
+	b, err := json.Marshal(FromError(joinError{errors.New("a"), nf}))
+	if err != nil || strings.Count(string(b), "The cat Lucie is missing") != 1 ||
+		!strings.Contains(string(b), `"Message":""`) {
+		t.Error("Expected the joined messages only in the causes", string(b), err)
+	}
+	f := FromError(summaryError{joinError{errors.New("a"), nf}})
+	if !strings.HasPrefix(f.Error(), "Fault: 2 lookups failed [Fault: a; NotFound") {
+		t.Error("Expected a message of its own to be kept", f.Error())
+	}
+}
@@ -0,0 +1,162 @@
+package utility_field
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// QuotaExceededStruct is an extension as it would be defined in another
+// package. It embeds the struct of its parent kind.
+type QuotaExceededStruct struct {
+	RuntimeFaultStruct
+	Limit int
+}
+
+func (q *QuotaExceededStruct) GetKind() string {
+	return "QuotaExceeded"
+}
+
+func (q *QuotaExceededStruct) MarshalJSON() ([]byte, error) {
+	type marshalable QuotaExceededStruct
+	return json.Marshal(struct {
+		Kind string
+		marshalable
+	}{
+		Kind:        "QuotaExceeded",
+		marshalable: marshalable(*q),
+	})
+}
+
+func (q *QuotaExceededStruct) UnmarshalJSON(in []byte) error {
+	pxy := &struct {
+		Message string
+		Cause   FaultField
+		Limit   int
+	}{}
+	err := json.Unmarshal(in, pxy)
+	if err != nil {
+		return err
+	}
+	q.Message = pxy.Message
+	q.Cause = pxy.Cause.Fault
+	q.Limit = pxy.Limit
+	return nil
+}
+
+// incompleteStruct embeds RuntimeFaultStruct but writes the RuntimeFault kind
+type incompleteStruct struct {
+	RuntimeFaultStruct
+}
+
+func (i *incompleteStruct) GetKind() string {
+	return "Incomplete"
+}
+
+func withExtension(test func()) {
+	withRegistry(func() {
+		RegisterExtension(polymorphic.Type{
+			Kind:   "QuotaExceeded",
+			Parent: "RuntimeFault",
+			New:    func() interface{} { return &QuotaExceededStruct{} },
+		})
+		test()
+	}, func(*polymorphic.Type) {})
+}
+
+func TestExtension(t *testing.T) {
+	withExtension(func() {
+		quota := &QuotaExceededStruct{
+			RuntimeFaultStruct: *runtimeFault,
+			Limit:              10,
+		}
+		b, err := json.Marshal(quota)
+		if err != nil {
+			t.Error("Serialization failed", err)
+			return
+		}
+
+		t.Log("JSON Bytes", string(b))
+
+		rtf, err := UnmarshalRuntimeFault(b)
+		if err != nil {
+			t.Error("Cannot deserialize extension", err)
+			return
+		}
+		q, ok := rtf.(*QuotaExceededStruct)
+		if !ok {
+			t.Error("Unexpected type", rtf)
+			return
+		}
+		if q.Limit != 10 || q.Message != "test message" {
+			t.Error("Unexpected extension fields", q.Limit, q.Message)
+		}
+		validateCause(q.Cause, t)
+		if !IsA("QuotaExceeded", "RuntimeFault") || KindOf(q) != "QuotaExceeded" {
+			t.Error("Expected extension in the hierarchy")
+		}
+	})
+}
+
+func TestInvalidExtension(t *testing.T) {
+	types := []polymorphic.Type{
+		{
+			Kind:   "Missing",
+			Parent: "Missing",
+			New:    func() interface{} { return &QuotaExceededStruct{} },
+		}, {
+			Kind:   "NotEmbedded",
+			Parent: "NotFound",
+			New:    func() interface{} { return &QuotaExceededStruct{} },
+		}, {
+			Kind:   "WrongKind",
+			Parent: "RuntimeFault",
+			New:    func() interface{} { return &QuotaExceededStruct{} },
+		}, {
+			Kind:   "Incomplete",
+			Parent: "RuntimeFault",
+			New:    func() interface{} { return &incompleteStruct{} },
+		},
+	}
+	for _, typ := range types {
+		func() {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Error("Expected registration to fail", typ.Kind)
+				}
+				t.Log("Registration error", r)
+			}()
+			withRegistry(func() { RegisterExtension(typ) }, func(*polymorphic.Type) {})
+		}()
+	}
+}
+
+func TestStrictExtension(t *testing.T) {
+	withExtension(func() {
+		valid := `{"Kind":"QuotaExceeded","Message":"m","Cause":{"Kind":"Fault","Message":"inner"},"Limit":10}`
+		_, err := UnmarshalFaultStrict([]byte(valid))
+		if err != nil {
+			t.Error("Cannot deserialize extension in strict mode", err)
+		}
+		invalid := map[string]string{
+			"member":       `{"Kind":"QuotaExceeded","Message":"m","Limt":10}`,
+			"cause kind":   `{"Kind":"QuotaExceeded","Cause":{"Kind":"Unknown"}}`,
+			"cause member": `{"Kind":"QuotaExceeded","Cause":{"Kind":"Fault","Mesage":"inner"}}`,
+			"causes":       `{"Kind":"QuotaExceeded","Causes":[{"Kind":"Fault","Mesage":"inner"}]}`,
+			"nested":       `{"Kind":"Fault","Cause":{"Kind":"QuotaExceeded","Limt":10}}`,
+		}
+		for name, in := range invalid {
+			_, err := UnmarshalFaultStrict([]byte(in))
+			if err == nil {
+				t.Error("Expected strict mode to fail for the", name)
+			}
+			t.Log(name, err)
+		}
+		f, err := UnmarshalFault([]byte(invalid["member"]))
+		if err != nil || f.GetKind() != "QuotaExceeded" {
+			t.Error("Expected lenient mode to ignore the misspelled member", err)
+		}
+	})
+}
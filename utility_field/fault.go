@@ -9,6 +9,9 @@ package utility_field
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // Fault represents a base error
@@ -16,10 +19,22 @@ import (
 // package and creative name will be needed e.g. NotFoundInterface.
 // The interface package approach seems cleaner.
 type Fault interface {
+	// fault seals the interface. Types outside of this package implement it
+	// by embedding FaultStruct.
+	fault()
+	error
+	GetKind() string
 	GetMessage() string
 	SetMessage(string)
+	GetMessageKey() string
+	SetMessageKey(string)
+	GetMessageArgs() []polymorphic.MessageArg
+	SetMessageArgs([]polymorphic.MessageArg)
+	LocalizedMessage(c *polymorphic.MessageCatalog, locale string) string
 	GetCause() Fault
 	SetCause(Fault)
+	GetCauses() []Fault
+	SetCauses([]Fault)
 }
 
 // FaultStruct contains information about a base fault
@@ -27,19 +42,92 @@ type Fault interface {
 // and the JSONSerializable
 type FaultStruct struct {
 	Message string
-	Cause   Fault
+	// MessageKey selects the template of the message in a
+	// polymorphic.MessageCatalog. Message is the default for clients without
+	// catalogs. The key and the arguments are written only when they are set.
+	MessageKey  string                   `json:",omitempty"`
+	MessageArgs []polymorphic.MessageArg `json:",omitempty"`
+	Cause       Fault
+	// Causes lists the failures aggregated by the fault such as the failed
+	// items of a batch. It is written only when it is not empty.
+	Causes []Fault `json:",omitempty"`
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind: "Fault",
+		New:  func() interface{} { return &FaultStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityError,
+			HTTPStatus: 500,
+			ExitCode:   1,
+		},
+	})
 }
 
 var _ Fault = &FaultStruct{}
 var _ json.Marshaler = &FaultStruct{}
 var _ json.Unmarshaler = &FaultStruct{}
+var _ fmt.Formatter = &FaultStruct{}
+
+// NewFault creates a Fault.
+// The options set the members inherited from FaultStruct like the message.
+func NewFault(opts ...Option) *FaultStruct {
+	x := &FaultStruct{}
+	apply(x, opts)
+	return x
+}
 
-// This assignment is not allowed with ZzRuntimeFault
+// This assignment is not allowed with the runtimeFault marker
 //var _ RuntimeFault = &Fault{}
 
 // This assignment is not allowed
 //var _ NotFound = &Fault{}
 
+// fault is a marker that seals the Fault interface
+func (fault *FaultStruct) fault() {
+}
+
+// GetKind returns the discriminator of the fault
+func (fault *FaultStruct) GetKind() string {
+	return "Fault"
+}
+
+// Error formats the fault with its kind, message and cause
+func (fault *FaultStruct) Error() string {
+	return formatError("Fault", fault.Message, "", fault.Cause, fault.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (fault *FaultStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, fault)
+}
+
+// Unwrap returns the cause so errors.Is and errors.As walk the cause chain
+func (fault *FaultStruct) Unwrap() error {
+	return unwrapCause(fault.Cause)
+}
+
+// UnwrapAll returns the cause followed by the causes for walking the whole
+// tree of failures
+func (fault *FaultStruct) UnwrapAll() []error {
+	return unwrapAll(fault.Cause, fault.Causes)
+}
+
+// Is reports whether target is in the chain of one of the causes. errors.Is
+// walks the chain of the cause through Unwrap.
+func (fault *FaultStruct) Is(target error) bool {
+	return isInCauses(fault.Causes, target)
+}
+
+// As finds the first error in the chains of the causes that matches target.
+// errors.As walks the chain of the cause through Unwrap after the causes.
+func (fault *FaultStruct) As(target interface{}) bool {
+	return asInCauses(fault.Causes, target)
+}
+
 // GetMessage retrieves the message value
 func (fault *FaultStruct) GetMessage() string {
 	return fault.Message
@@ -50,6 +138,32 @@ func (fault *FaultStruct) SetMessage(message string) {
 	fault.Message = message
 }
 
+// GetMessageKey retrieves the key of the localizable message
+func (fault *FaultStruct) GetMessageKey() string {
+	return fault.MessageKey
+}
+
+// SetMessageKey sets the key of the localizable message
+func (fault *FaultStruct) SetMessageKey(key string) {
+	fault.MessageKey = key
+}
+
+// GetMessageArgs retrieves the arguments of the localizable message
+func (fault *FaultStruct) GetMessageArgs() []polymorphic.MessageArg {
+	return fault.MessageArgs
+}
+
+// SetMessageArgs sets the arguments of the localizable message
+func (fault *FaultStruct) SetMessageArgs(args []polymorphic.MessageArg) {
+	fault.MessageArgs = args
+}
+
+// LocalizedMessage renders the message in locale with the catalog. It is
+// Message if the fault has no key or the catalog has no template for it.
+func (fault *FaultStruct) LocalizedMessage(c *polymorphic.MessageCatalog, locale string) string {
+	return c.Render(locale, fault.MessageKey, fault.MessageArgs, fault.Message)
+}
+
 // GetCause returns the case of fault
 func (fault *FaultStruct) GetCause() Fault {
 	return fault.Cause
@@ -60,67 +174,131 @@ func (fault *FaultStruct) SetCause(cause Fault) {
 	fault.Cause = cause
 }
 
+// GetCauses returns the aggregated causes of the fault
+func (fault *FaultStruct) GetCauses() []Fault {
+	return fault.Causes
+}
+
+// SetCauses sets the aggregated causes of the fault
+func (fault *FaultStruct) SetCauses(causes []Fault) {
+	fault.Causes = causes
+}
+
 // UnmarshalJSON reads a fault from JSON
 func (fault *FaultStruct) UnmarshalJSON(in []byte) error {
+	return fault.unmarshal(in, false)
+}
+
+func (fault *FaultStruct) unmarshal(in []byte, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   FaultField
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         FaultField
+		Causes        FaultsField
 	}{}
-	err := json.Unmarshal(in, pxy)
+	pxy.Cause.strict = strict
+	pxy.Causes.strict = strict
+	err := unmarshalProxy(in, pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "Fault", strict)
 	if err != nil {
 		return err
 	}
 	fault.Message = pxy.Message
+	fault.MessageKey = pxy.MessageKey
+	fault.MessageArgs = pxy.MessageArgs
 	fault.Cause = pxy.Cause.Fault
+	fault.Causes = pxy.Causes.Faults
 	return nil
 }
 
 // MarshalJSON writes Fault as JSON and adds discriminator
 func (fault *FaultStruct) MarshalJSON() ([]byte, error) {
-	type marshalable FaultStruct
-	// The approach below copies the full object into a temporary object
-	// with discriminator and passes it to the go json mashaler.
-	// An alternative is to create small utility object that holds the
-	// discriminator and anonymous Fault pointer. This requires the
-	// serialization to be in custom interface and additional copy logic in
-	// higher level bindings. The current approach preserves the go abstractions
-	// and simplifies higher level bindings.
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "Fault",
-		marshalable: marshalable(*fault),
-	})
+	return marshal(fault)
+}
+
+// encode writes the Kind first and then the members directly. Unlike the
+// json.Marshal of a struct that embeds the fault and adds the discriminator it
+// does not copy the fault and writes nested causes into the same buffer.
+func (fault *FaultStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "Fault")
+	if err != nil {
+		return err
+	}
+	err = fault.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.EndObject()
+	return nil
+}
+
+// encodeMembers writes the members declared by FaultStruct
+func (fault *FaultStruct) encodeMembers(e *polymorphic.Encoder) error {
+	e.Name("Message")
+	e.String(fault.Message)
+	if fault.MessageKey != "" {
+		e.Name("MessageKey")
+		e.String(fault.MessageKey)
+	}
+	if len(fault.MessageArgs) > 0 {
+		e.Name("MessageArgs")
+		err := e.Marshal(fault.MessageArgs)
+		if err != nil {
+			return err
+		}
+	}
+	e.Name("Cause")
+	err := encodeFault(e, fault.Cause)
+	if err != nil || len(fault.Causes) == 0 {
+		return err
+	}
+	e.Name("Causes")
+	return encodeFaults(e, fault.Causes)
 }
 
 // UnmarshalFault reads a fault from JSON and instantiates the proper type
-// based on the Kind field. It deserializes the value twice. First scan for
-// discriminator and then deserializes into the proper type.
+// based on the Kind field. It scans for the discriminator and then
+// deserializes into the proper type.
 func UnmarshalFault(in []byte) (Fault, error) {
-	d := &struct {
-		Kind string
-	}{}
-	// Double pointer detects null values
-	err := json.Unmarshal(in, &d)
+	return unmarshalFault(in, false)
+}
+
+func unmarshalFault(in []byte, strict bool) (Fault, error) {
+	// The scan stops at the Kind member so the value is parsed in full only
+	// once when it is read into the concrete type.
+	kind, ok, err := discriminator.ScanKind(in)
 	if err != nil {
 		return nil, err
 	}
-	if d == nil {
+	if !ok {
 		return nil, nil
 	}
-	kind := d.Kind
+	// Documents written with an older schema are migrated to the current
+	// shape before they are read.
+	if migrations.Migrates(kind) {
+		var upgraded string
+		in, upgraded, err = migrations.Upgrade(in, string(kind))
+		if err != nil {
+			return nil, err
+		}
+		kind = []byte(upgraded)
+	}
 
-	var res Fault
-	switch kind {
-	case "NotFound":
-		res = &NotFoundStruct{}
-	case "RuntimeFault":
-		res = &RuntimeFaultStruct{}
-	default: // Error on default or try to use base type?
+	res := newFault(kind)
+	if res == nil {
+		if strict {
+			return nil, fmt.Errorf("unknown kind %q", kind)
+		}
+		// Lenient decoding falls back to the base type
 		res = &FaultStruct{}
 	}
-	err = json.Unmarshal(in, res)
+	err = unmarshalInto(res, in, strict)
 	if err != nil {
 		return nil, err
 	}
@@ -128,6 +306,16 @@ func UnmarshalFault(in []byte) (Fault, error) {
 	return res, nil
 }
 
+// newFault instantiates the registered type for the kind bytes or returns nil
+// if the kind is unknown. The dispatch does not allocate a string for the kind.
+func newFault(kind []byte) Fault {
+	res := registry.NewBytes(kind)
+	if res == nil {
+		return nil
+	}
+	return res.(Fault)
+}
+
 // FaultField is utility class that helps the go JSON deserializer to invoke the
 // proper de-serialization logic for Fault fields while preserving the
 // polymorphic nature of the type. go uses reflection to invoke the proper
@@ -140,6 +328,8 @@ func UnmarshalFault(in []byte) (Fault, error) {
 // See field_test.go
 type FaultField struct {
 	Fault
+	// strict is set by the enclosing proxy to read the fault in strict mode
+	strict bool
 }
 
 var _ Fault = &FaultField{}
@@ -148,10 +338,49 @@ var _ json.Unmarshaler = &FaultField{}
 // UnmarshalJSON reads the embedded fault taking care of the discriminator
 func (ff *FaultField) UnmarshalJSON(in []byte) error {
 	var err error
-	ff.Fault, err = UnmarshalFault(in)
+	ff.Fault, err = unmarshalFault(in, ff.strict)
 	return err
 }
 
+// Error returns the error of the Fault or "<nil>" when the field is null. The
+// one promoted from the interface fails on null fields.
+func (ff FaultField) Error() string {
+	return fieldError(ff.Fault)
+}
+
+// Unwrap returns the Fault so errors.As finds it through the field
+func (ff FaultField) Unwrap() error {
+	return unwrapCause(ff.Fault)
+}
+
+// FaultsField reads a polymorphic array of faults such as Causes. The
+// elements are read with the discriminator like FaultField. Null is no faults.
+type FaultsField struct {
+	Faults []Fault
+	// strict is set by the enclosing proxy to read the faults in strict mode
+	strict bool
+}
+
+var _ json.Unmarshaler = &FaultsField{}
+
+// UnmarshalJSON reads the faults taking care of their discriminators
+func (ff *FaultsField) UnmarshalJSON(in []byte) error {
+	elements, err := polymorphic.SplitArray(in)
+	if err != nil || elements == nil {
+		ff.Faults = nil
+		return err
+	}
+	faults := make([]Fault, len(elements))
+	for i, element := range elements {
+		faults[i], err = unmarshalFault(element, ff.strict)
+		if err != nil {
+			return &polymorphic.ElementError{Index: i, Err: err}
+		}
+	}
+	ff.Faults = faults
+	return nil
+}
+
 // ToFaultsArray is utility to convert FaultField Array to Fault array
 func ToFaultsArray(faults []FaultField) []Fault {
 	var items []Fault
@@ -2,6 +2,8 @@ package utility_field
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -47,3 +49,26 @@ func TestContainerField(t *testing.T) {
 
 	validateNotFound(c.FaultField, t)
 }
+
+func TestNullFieldError(t *testing.T) {
+	temp := struct {
+		FaultField    FaultField
+		NotFoundField NotFoundField
+	}{}
+	err := json.Unmarshal([]byte(`{"FaultField":null}`), &temp)
+	if err != nil {
+		t.Error("Cannot deserialize null fields", err)
+		return
+	}
+	if s := fmt.Sprintf("%+v", temp); s != "{FaultField:<nil> NotFoundField:<nil>}" {
+		t.Error("Unexpected null fields", s)
+	}
+	if temp.FaultField.Error() != "<nil>" || temp.FaultField.Unwrap() != nil {
+		t.Error("Expected a null field to have no error")
+	}
+	ff := FaultField{Fault: notFound}
+	var nf NotFound
+	if ff.Error() != notFound.Error() || !errors.As(ff, &nf) || nf != notFound {
+		t.Error("Expected the field to wrap the fault", ff.Error())
+	}
+}
@@ -0,0 +1,119 @@
+package utility_field
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// formatFault implements fmt.Formatter for the faults. %v and %s write the
+// one line Error, %q quotes it and %+v writes the kind and all members of the
+// fault followed by its cause and causes indented below it, e.g.
+//
+//	NotFound: The cat Lucie is missing
+//	  ObjKind: Cat
+//	  Obj: Lucie
+//	  Cause: RuntimeFault: The shelter is closed
+func formatFault(s fmt.State, verb rune, f Fault) {
+	if isNil(f) {
+		io.WriteString(s, "<nil>")
+		return
+	}
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, verboseFault(f, ""))
+			return
+		}
+		io.WriteString(s, f.Error())
+	case 's':
+		io.WriteString(s, f.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", f.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, f.Error())
+	}
+}
+
+// verboseFault writes the fault for %+v with each line below the first one
+// prefixed by indent
+func verboseFault(f Fault, indent string) string {
+	if isNil(f) {
+		return "<nil>"
+	}
+	var b strings.Builder
+	indent += "  "
+	b.WriteString(f.GetKind())
+	if f.GetMessage() != "" {
+		b.WriteString(": ")
+		b.WriteString(continueLines(f.GetMessage(), indent))
+	}
+	v := reflect.ValueOf(f)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		writeMembers(&b, v, indent)
+	}
+	if cause := f.GetCause(); !isNil(cause) {
+		b.WriteString("\n" + indent + "Cause: ")
+		b.WriteString(verboseFault(cause, indent))
+	}
+	for i, c := range f.GetCauses() {
+		fmt.Fprintf(&b, "\n%sCauses[%d]: ", indent, i)
+		b.WriteString(verboseFault(c, indent))
+	}
+	return b.String()
+}
+
+// writeMembers writes the exported members of the struct and the structs it
+// embeds one per line. The message and the causes are written separately.
+// Members that JSON omits when empty are left out when they are empty.
+func writeMembers(b *strings.Builder, v reflect.Value, indent string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			writeMembers(b, fv, indent)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		switch sf.Name {
+		case "Message", "Cause", "Causes":
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" || strings.Contains(tag, "omitempty") && fv.IsZero() {
+			continue
+		}
+		b.WriteString("\n" + indent + sf.Name + ": ")
+		if args, ok := fv.Interface().([]polymorphic.MessageArg); ok {
+			writeMessageArgs(b, args)
+		} else {
+			b.WriteString(continueLines(fmt.Sprint(fv.Interface()), indent))
+		}
+	}
+}
+
+// continueLines indents the lines after the first one two spaces deeper than
+// the members so messages with new lines such as the ones of joined errors
+// stay below their fault
+func continueLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent+"  ")
+}
+
+// writeMessageArgs writes the arguments as name=value pairs
+func writeMessageArgs(b *strings.Builder, args []polymorphic.MessageArg) {
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(b, "%s=%v", arg.Name, arg.Value)
+	}
+}
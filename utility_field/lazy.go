@@ -0,0 +1,136 @@
+package utility_field
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// LazyFault holds a fault as JSON and decodes it on first access. GetKind and
+// IsA read the discriminator recorded when the JSON was read so routers that
+// only look at the kind never decode the fault. MarshalJSON writes the
+// original bytes back unless the fault was modified after it was decoded.
+type LazyFault struct {
+	raw     []byte
+	kind    string
+	fault   Fault
+	decoded bool
+	err     error
+	// snapshot is the encoding of the fault when it was decoded. The fault is
+	// modified if its encoding differs.
+	snapshot []byte
+}
+
+var _ json.Marshaler = LazyFault{}
+var _ json.Unmarshaler = &LazyFault{}
+
+// UnmarshalJSON records the JSON and the discriminator without decoding the
+// fault. Documents written with an older schema are upgraded first so the
+// kind and the bytes are at the current schema.
+func (l *LazyFault) UnmarshalJSON(in []byte) error {
+	kind, ok, err := discriminator.ScanKind(in)
+	if err != nil {
+		return err
+	}
+	raw := append([]byte(nil), in...)
+	if ok && migrations.Migrates(kind) {
+		var upgraded string
+		raw, upgraded, err = migrations.Upgrade(raw, string(kind))
+		if err != nil {
+			return err
+		}
+		kind = []byte(upgraded)
+	}
+	*l = LazyFault{raw: raw, kind: string(kind)}
+	return nil
+}
+
+// GetKind returns the discriminator of the fault or empty string for null
+func (l *LazyFault) GetKind() string {
+	return l.kind
+}
+
+// IsA reports whether the fault is of kind or one of its descendants. It does
+// not decode the fault.
+func (l *LazyFault) IsA(kind string) bool {
+	return l.kind != "" && registry.IsA(l.kind, kind)
+}
+
+// Fault decodes the fault on the first call and returns it. Changes to the
+// returned fault are written by MarshalJSON.
+func (l *LazyFault) Fault() (Fault, error) {
+	if !l.decoded {
+		l.decoded = true
+		l.fault, l.err = unmarshalFault(l.raw, false)
+		if l.err == nil {
+			l.snapshot, l.err = marshalFault(l.fault)
+		}
+	}
+	return l.fault, l.err
+}
+
+// As decodes the fault and assigns it to the variable target points to if
+// the fault has its type. It is false if the fault cannot be decoded. Fault
+// returns the error.
+//
+//	var nf NotFound
+//	if lazy.As(&nf) {
+//		...
+//	}
+func (l *LazyFault) As(target interface{}) bool {
+	fault, err := l.Fault()
+	if err != nil || fault == nil {
+		return false
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	f := reflect.ValueOf(fault)
+	if !f.Type().AssignableTo(v.Elem().Type()) {
+		return false
+	}
+	v.Elem().Set(f)
+	return true
+}
+
+// Set replaces the fault. MarshalJSON writes the new fault.
+func (l *LazyFault) Set(fault Fault) {
+	kind := ""
+	if fault != nil {
+		kind = fault.GetKind()
+	}
+	*l = LazyFault{kind: kind, fault: fault, decoded: true}
+}
+
+// MarshalJSON writes the original JSON if the fault was not decoded or was not
+// modified since. Members the bindings do not know are preserved in that case.
+func (l LazyFault) MarshalJSON() ([]byte, error) {
+	if !l.decoded || l.err != nil {
+		if l.raw == nil {
+			return []byte("null"), nil
+		}
+		return l.raw, nil
+	}
+	out, err := marshalFault(l.fault)
+	if err != nil {
+		return nil, err
+	}
+	if l.raw != nil && bytes.Equal(out, l.snapshot) {
+		return l.raw, nil
+	}
+	return out, nil
+}
+
+// marshalFault encodes the fault or null into a new slice
+func marshalFault(f Fault) ([]byte, error) {
+	e := polymorphic.AcquireEncoder()
+	defer polymorphic.ReleaseEncoder(e)
+	err := encodeFault(e, f)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), e.Bytes()...), nil
+}
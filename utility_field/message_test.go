@@ -0,0 +1,65 @@
+package utility_field
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// localizedJSON carries the key, the arguments and the default message
+const localizedJSON = `{"Kind":"NotFound","Message":"Virtual machine vm-42 was not found",` +
+	`"MessageKey":"vm.notFound","MessageArgs":[{"Name":"vm","Type":"string","Value":"vm-42"}],` +
+	`"Cause":null,"ObjKind":"VirtualMachine","Obj":"vm-42"}`
+
+func TestLocalizedMessage(t *testing.T) {
+	nf := &NotFoundStruct{ObjKind: "VirtualMachine", Obj: "vm-42"}
+	nf.Message = "Virtual machine vm-42 was not found"
+	nf.MessageKey = "vm.notFound"
+	nf.MessageArgs = []polymorphic.MessageArg{{Name: "vm", Value: "vm-42"}}
+	b, err := json.Marshal(nf)
+	if err != nil || string(b) != localizedJSON {
+		t.Error("Unexpected JSON", string(b), err)
+		return
+	}
+	f, err := UnmarshalFaultStrict(b)
+	if err != nil {
+		t.Error("Cannot deserialize localizable message", err)
+		return
+	}
+	c := polymorphic.NewMessageCatalog("en")
+	c.Add("de", map[string]string{"vm.notFound": "Virtuelle Maschine {vm} wurde nicht gefunden"})
+	fault := f
+	if s := fault.LocalizedMessage(c, "de-DE"); s != "Virtuelle Maschine vm-42 wurde nicht gefunden" {
+		t.Error("Unexpected localized message", s)
+	}
+	if s := fault.LocalizedMessage(c, "fr"); s != nf.Message {
+		t.Error("Expected the default message", s)
+	}
+	old, err := UnmarshalFault([]byte(notFoundJSON))
+	if err != nil || old.GetMessageKey() != "" || old.LocalizedMessage(c, "de") != "test message" {
+		t.Error("Expected faults without key to keep their message", err)
+	}
+}
+
+func TestLocalizedIntegerArgs(t *testing.T) {
+	nf := NewNotFound("VirtualMachine", "vm-42", WithMessageKey("vm.count",
+		polymorphic.MessageArg{Name: "count", Value: 12345678},
+		polymorphic.MessageArg{Name: "total", Value: int64(9007199254740993)},
+		polymorphic.MessageArg{Name: "ratio", Value: 0.5}))
+	b, err := json.Marshal(nf)
+	if err != nil {
+		t.Error("Serialization failed", err)
+		return
+	}
+	f, err := UnmarshalFaultStrict(b)
+	if err != nil {
+		t.Error("Cannot deserialize integer arguments", err, string(b))
+		return
+	}
+	c := polymorphic.NewMessageCatalog("en")
+	c.Add("en", map[string]string{"vm.count": "count {count} of {total} at {ratio}"})
+	if s := f.LocalizedMessage(c, "en"); s != "count 12345678 of 9007199254740993 at 0.5" {
+		t.Error("Unexpected localized message", s, string(b))
+	}
+}
@@ -3,6 +3,9 @@ package utility_field
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // NotFound represents error when object is not found
@@ -13,7 +16,9 @@ type NotFound interface {
 	SetObjKind(string)
 	GetObj() string
 	SetObj(string)
-	ZzNotFound()
+	// notFound seals the interface. Types outside of this package implement
+	// it by embedding NotFoundStruct.
+	notFound()
 }
 
 // NotFoundStruct contains the data about a not found error
@@ -23,15 +28,56 @@ type NotFoundStruct struct {
 	Obj     string
 }
 
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "NotFound",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &NotFoundStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.NotRetryable,
+			Severity:   polymorphic.SeverityWarning,
+			HTTPStatus: 404,
+			ExitCode:   66, // EX_NOINPUT
+		},
+	})
+}
+
 var _ NotFound = &NotFoundStruct{}
 var _ RuntimeFault = &NotFoundStruct{}
 var _ Fault = &NotFoundStruct{}
 var _ json.Marshaler = &NotFoundStruct{}
 var _ json.Unmarshaler = &NotFoundStruct{}
+var _ fmt.Formatter = &NotFoundStruct{}
+
+// NewNotFound creates a NotFound for the object of objKind with id obj.
+// The options set the members inherited from FaultStruct like the message.
+func NewNotFound(objKind, obj string, opts ...Option) *NotFoundStruct {
+	x := &NotFoundStruct{ObjKind: objKind, Obj: obj}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (nfo *NotFoundStruct) GetKind() string {
+	return "NotFound"
+}
 
-// ZzNotFound is a marker to prevent converting struct with same fields into
+// Error formats the fault with its kind, message, the object that is not
+// found and the cause
+func (nfo *NotFoundStruct) Error() string {
+	details := strings.TrimSpace(nfo.ObjKind + " " + nfo.Obj)
+	return formatError("NotFound", nfo.Message, details, nfo.Cause, nfo.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (nfo *NotFoundStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, nfo)
+}
+
+// notFound is a marker to prevent converting struct with same fields into
 // NotFound interface
-func (nfo *NotFoundStruct) ZzNotFound() {
+func (nfo *NotFoundStruct) notFound() {
 }
 
 // GetObjKind retrieves the object kind of obj identifier
@@ -56,30 +102,59 @@ func (nfo *NotFoundStruct) SetObj(obj string) {
 
 // MarshalJSON writes a NotFoundObject as JSON
 func (nfo *NotFoundStruct) MarshalJSON() ([]byte, error) {
-	type marshalable NotFoundStruct
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "NotFound",
-		marshalable: marshalable(*nfo),
-	})
+	return marshal(nfo)
+}
+
+// encode writes the Kind first and then the members directly
+func (nfo *NotFoundStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "NotFound")
+	if err != nil {
+		return err
+	}
+	err = nfo.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("ObjKind")
+	e.String(nfo.ObjKind)
+	e.Name("Obj")
+	e.String(nfo.Obj)
+	e.EndObject()
+	return nil
 }
 
 // UnmarshalJSON reads a NotFound from JSON
 func (nfo *NotFoundStruct) UnmarshalJSON(in []byte) error {
+	return nfo.unmarshal(in, false)
+}
+
+func (nfo *NotFoundStruct) unmarshal(in []byte, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   FaultField
-		ObjKind string
-		Obj     string
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         FaultField
+		Causes        FaultsField
+		ObjKind       string
+		Obj           string
 	}{}
-	err := json.Unmarshal(in, pxy)
+	pxy.Cause.strict = strict
+	pxy.Causes.strict = strict
+	err := unmarshalProxy(in, pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "NotFound", strict)
 	if err != nil {
 		return err
 	}
 	nfo.Message = pxy.Message
+	nfo.MessageKey = pxy.MessageKey
+	nfo.MessageArgs = pxy.MessageArgs
 	nfo.Cause = pxy.Cause.Fault
+	nfo.Causes = pxy.Causes.Faults
 	nfo.Obj = pxy.Obj
 	nfo.ObjKind = pxy.ObjKind
 	return nil
@@ -100,6 +175,17 @@ func (ff *NotFoundField) UnmarshalJSON(in []byte) error {
 	return err
 }
 
+// Error returns the error of the NotFound or "<nil>" when the field is null.
+// The one promoted from the interface fails on null fields.
+func (ff NotFoundField) Error() string {
+	return fieldError(ff.NotFound)
+}
+
+// Unwrap returns the NotFound so errors.As finds it through the field
+func (ff NotFoundField) Unwrap() error {
+	return unwrapCause(ff.NotFound)
+}
+
 // UnmarshalNotFound reads NotFound or it's subclasses from JSON bytes
 func UnmarshalNotFound(in []byte) (NotFound, error) {
 	fault, err := UnmarshalFault(in)
@@ -109,5 +195,5 @@ func UnmarshalNotFound(in []byte) (NotFound, error) {
 	if notFound, ok := fault.(NotFound); ok {
 		return notFound, nil
 	}
-	return nil, fmt.Errorf("cannot unmarshal NotFound %v", fault)
+	return nil, fmt.Errorf("cannot unmarshal NotFound from %v", fault)
 }
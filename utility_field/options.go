@@ -0,0 +1,55 @@
+package utility_field
+
+import (
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// Option sets a member that every fault inherits from FaultStruct. The
+// constructors apply the options to the embedded FaultStruct so they keep
+// working when levels are added to the hierarchy.
+//
+//	nf := NewNotFound("VirtualMachine", "vm-42", WithMessage("not found"), WithCause(cause))
+type Option func(f *FaultStruct)
+
+// WithMessage sets the message
+func WithMessage(message string) Option {
+	return func(f *FaultStruct) {
+		f.Message = message
+	}
+}
+
+// WithMessagef sets the message formatted like fmt.Sprintf
+func WithMessagef(format string, args ...interface{}) Option {
+	return WithMessage(fmt.Sprintf(format, args...))
+}
+
+// WithMessageKey sets the key and the arguments of the localizable message
+func WithMessageKey(key string, args ...polymorphic.MessageArg) Option {
+	return func(f *FaultStruct) {
+		f.MessageKey = key
+		f.MessageArgs = args
+	}
+}
+
+// WithCause sets the cause
+func WithCause(cause Fault) Option {
+	return func(f *FaultStruct) {
+		f.Cause = cause
+	}
+}
+
+// WithCauses sets the aggregated causes
+func WithCauses(causes ...Fault) Option {
+	return func(f *FaultStruct) {
+		f.Causes = causes
+	}
+}
+
+// apply runs the options on the fault
+func apply(f *FaultStruct, opts []Option) {
+	for _, opt := range opts {
+		opt(f)
+	}
+}
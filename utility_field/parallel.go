@@ -0,0 +1,26 @@
+package utility_field
+
+import "github.com/karaatanassov/go_polymorphic_json/polymorphic"
+
+// UnmarshalFaultsParallel reads a JSON array of faults. The elements are
+// decoded in up to workers goroutines and returned in their original order.
+// Zero or less workers use GOMAXPROCS. Decoding stops at the first failure and
+// the error is *polymorphic.ElementError with the index of the element. It
+// pays off for large arrays on hosts with several cores. Small arrays decode
+// faster with UnmarshalFault on each element.
+func UnmarshalFaultsParallel(in []byte, workers int) ([]Fault, error) {
+	elements, err := polymorphic.SplitArray(in)
+	if err != nil || elements == nil {
+		return nil, err
+	}
+	faults := make([]Fault, len(elements))
+	err = polymorphic.DecodeParallel(len(elements), workers, func(i int) error {
+		var err error
+		faults[i], err = UnmarshalFault(elements[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return faults, nil
+}
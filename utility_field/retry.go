@@ -0,0 +1,37 @@
+package utility_field
+
+import (
+	"context"
+	"errors"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// Retry calls fn until it succeeds or fails with an error that is not worth
+// retrying. The first fault in the chain of the error decides: the rule of the
+// policy for its kind or nearest ancestor, else the classification of the
+// kind. Terminal kinds such as NotFound stop at once. Errors without a fault
+// are not retried.
+//
+//	err := utility_field.Retry(ctx, polymorphic.RetryPolicy{MaxAttempts: 5}, func(ctx context.Context) error {
+//		return client.PowerOn(ctx, vm)
+//	})
+func Retry(ctx context.Context, policy polymorphic.RetryPolicy, fn func(ctx context.Context) error) error {
+	return polymorphic.Retry(ctx, policy, func(err error) polymorphic.RetryRule {
+		return retryRule(policy, err)
+	}, fn)
+}
+
+// retryRule resolves the rule for the fault in the chain of err
+func retryRule(policy polymorphic.RetryPolicy, err error) polymorphic.RetryRule {
+	var f Fault
+	if !errors.As(err, &f) || isNil(f) {
+		return polymorphic.RetryRule{Retry: polymorphic.NotRetryable}
+	}
+	kind := faultKind(f)
+	rule := policy.Rule(append([]string{kind}, registry.Ancestors(kind)...)...)
+	if rule.Retry == polymorphic.RetryUnspecified {
+		rule.Retry = registry.Classify(kind).Retry
+	}
+	return rule
+}
@@ -3,14 +3,18 @@ package utility_field
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
 )
 
 // RuntimeFault the descends from Fault and adds no new fields just semantics.
 // To be generated
 type RuntimeFault interface {
 	Fault
-	// ZzRuntimeFault disallows converting Fault struct to RuntimeFault interface
-	ZzRuntimeFault()
+	// runtimeFault disallows converting Fault struct to RuntimeFault interface.
+	// It is unexported to seal the interface. Types outside of this package
+	// implement it by embedding RuntimeFaultStruct.
+	runtimeFault()
 }
 
 // RuntimeFaultStruct contains the RuntimeFault data
@@ -18,40 +22,103 @@ type RuntimeFaultStruct struct {
 	FaultStruct
 }
 
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "RuntimeFault",
+		Parent: "Fault",
+		New:    func() interface{} { return &RuntimeFaultStruct{} },
+		Classification: polymorphic.Classification{
+			Retry:      polymorphic.Retryable,
+			HTTPStatus: 503,
+			ExitCode:   75, // EX_TEMPFAIL of sysexits.h
+		},
+	})
+}
+
 var _ Fault = &RuntimeFaultStruct{}
 var _ RuntimeFault = &RuntimeFaultStruct{}
 var _ json.Marshaler = &RuntimeFaultStruct{}
 var _ json.Unmarshaler = &RuntimeFaultStruct{}
+var _ fmt.Formatter = &RuntimeFaultStruct{}
+
+// NewRuntimeFault creates a RuntimeFault.
+// The options set the members inherited from FaultStruct like the message.
+func NewRuntimeFault(opts ...Option) *RuntimeFaultStruct {
+	x := &RuntimeFaultStruct{}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (rf *RuntimeFaultStruct) GetKind() string {
+	return "RuntimeFault"
+}
 
-// ZzRuntimeFault is a marker it prevents converting Fault struct to
+// Error formats the fault with its kind, message and cause
+func (rf *RuntimeFaultStruct) Error() string {
+	return formatError("RuntimeFault", rf.Message, "", rf.Cause, rf.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (rf *RuntimeFaultStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, rf)
+}
+
+// runtimeFault is a marker it prevents converting Fault struct to
 // RuntimeFault interface
-func (rf *RuntimeFaultStruct) ZzRuntimeFault() {
+func (rf *RuntimeFaultStruct) runtimeFault() {
 }
 
 // MarshalJSON writes a RuntimeFaultObject as JSON
 func (rf *RuntimeFaultStruct) MarshalJSON() ([]byte, error) {
-	type marshalable RuntimeFaultStruct
-	return json.Marshal(struct {
-		Kind string
-		marshalable
-	}{
-		Kind:        "RuntimeFault",
-		marshalable: marshalable(*rf),
-	})
+	return marshal(rf)
+}
+
+// encode writes the Kind first and then the members directly
+func (rf *RuntimeFaultStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "RuntimeFault")
+	if err != nil {
+		return err
+	}
+	err = rf.FaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.EndObject()
+	return nil
 }
 
 // UnmarshalJSON reads a RuntimeFault from JSON
 func (rf *RuntimeFaultStruct) UnmarshalJSON(in []byte) error {
+	return rf.unmarshal(in, false)
+}
+
+func (rf *RuntimeFaultStruct) unmarshal(in []byte, strict bool) error {
 	pxy := &struct {
-		Message string
-		Cause   FaultField
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         FaultField
+		Causes        FaultsField
 	}{}
-	err := json.Unmarshal(in, pxy)
+	pxy.Cause.strict = strict
+	pxy.Causes.strict = strict
+	err := unmarshalProxy(in, pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "RuntimeFault", strict)
 	if err != nil {
 		return err
 	}
 	rf.Message = pxy.Message
+	rf.MessageKey = pxy.MessageKey
+	rf.MessageArgs = pxy.MessageArgs
 	rf.Cause = pxy.Cause.Fault
+	rf.Causes = pxy.Causes.Faults
 	return nil
 }
 
@@ -70,6 +137,17 @@ func (ff *RuntimeFaultField) UnmarshalJSON(in []byte) error {
 	return err
 }
 
+// Error returns the error of the RuntimeFault or "<nil>" when the field is
+// null. The one promoted from the interface fails on null fields.
+func (ff RuntimeFaultField) Error() string {
+	return fieldError(ff.RuntimeFault)
+}
+
+// Unwrap returns the RuntimeFault so errors.As finds it through the field
+func (ff RuntimeFaultField) Unwrap() error {
+	return unwrapCause(ff.RuntimeFault)
+}
+
 // UnmarshalRuntimeFault reads RuntimeFault and it's subclasses from JSON bytes
 func UnmarshalRuntimeFault(in []byte) (RuntimeFault, error) {
 	fault, err := UnmarshalFault(in)
@@ -79,5 +157,5 @@ func UnmarshalRuntimeFault(in []byte) (RuntimeFault, error) {
 	if runtimeFault, ok := fault.(RuntimeFault); ok {
 		return runtimeFault, nil
 	}
-	return nil, fmt.Errorf("cannot unmarshal RuntimeFault %v", fault)
+	return nil, fmt.Errorf("cannot unmarshal RuntimeFault from %v", fault)
 }
@@ -0,0 +1,40 @@
+package utility_field
+
+import "github.com/karaatanassov/go_polymorphic_json/polymorphic"
+
+// unmarshaler is implemented by all fault structs. It allows the strict flag
+// to reach the nested Cause fields.
+type unmarshaler interface {
+	unmarshal(in []byte, strict bool) error
+}
+
+var _ unmarshaler = &FaultStruct{}
+var _ unmarshaler = &RuntimeFaultStruct{}
+var _ unmarshaler = &NotFoundStruct{}
+var _ unmarshaler = &InvalidArgumentStruct{}
+var _ unmarshaler = &AlreadyExistsStruct{}
+var _ unmarshaler = &PermissionDeniedStruct{}
+var _ unmarshaler = &TimeoutStruct{}
+var _ unmarshaler = &ConflictStruct{}
+var _ unmarshaler = &ResourceExhaustedStruct{}
+
+// UnmarshalFaultStrict reads a fault from JSON like UnmarshalFault. It fails
+// on members that are not known to the kind and on unknown Kind values instead
+// of ignoring them. Nested causes are read in strict mode too.
+func UnmarshalFaultStrict(in []byte) (Fault, error) {
+	return unmarshalFault(in, true)
+}
+
+// UnmarshalStrict reads JSON into the fault struct v. It fails on unknown
+// members and when the Kind in the payload is neither the type of v nor one of
+// its descendants. Extensions registered from other packages are read with
+// their own UnmarshalJSON.
+func UnmarshalStrict(in []byte, v Fault) error {
+	return unmarshalInto(v, in, true)
+}
+
+// unmarshalProxy reads the JSON into the proxy struct. In strict mode members
+// that are not in the proxy are reported as errors.
+func unmarshalProxy(in []byte, pxy interface{}, strict bool) error {
+	return polymorphic.Unmarshal(in, pxy, strict)
+}
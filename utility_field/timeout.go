@@ -0,0 +1,176 @@
+package utility_field
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karaatanassov/go_polymorphic_json/polymorphic"
+)
+
+// Timeout represents error when an operation does not complete in time
+type Timeout interface {
+	RuntimeFault
+	GetOperation() string
+	SetOperation(string)
+	// timeout seals the interface. Types outside of this package
+	// implement it by embedding TimeoutStruct.
+	timeout()
+}
+
+// TimeoutStruct contains the data about an operation that timed out
+type TimeoutStruct struct {
+	RuntimeFaultStruct
+	Operation string
+}
+
+func init() {
+	registry.Register(polymorphic.Type{
+		Kind:   "Timeout",
+		Parent: "RuntimeFault",
+		New:    func() interface{} { return &TimeoutStruct{} },
+		Classification: polymorphic.Classification{
+			HTTPStatus: 504,
+		},
+	})
+}
+
+var _ Timeout = &TimeoutStruct{}
+var _ RuntimeFault = &TimeoutStruct{}
+var _ Fault = &TimeoutStruct{}
+var _ json.Marshaler = &TimeoutStruct{}
+var _ json.Unmarshaler = &TimeoutStruct{}
+var _ fmt.Formatter = &TimeoutStruct{}
+
+// NewTimeout creates a Timeout of the operation.
+// The options set the members inherited from FaultStruct like the message.
+func NewTimeout(operation string, opts ...Option) *TimeoutStruct {
+	x := &TimeoutStruct{Operation: operation}
+	apply(&x.FaultStruct, opts)
+	return x
+}
+
+// GetKind returns the discriminator of the fault
+func (to *TimeoutStruct) GetKind() string {
+	return "Timeout"
+}
+
+// Error formats the fault with its kind, message, the operation that timed out
+// and the cause
+func (to *TimeoutStruct) Error() string {
+	return formatError("Timeout", to.Message, to.Operation, to.Cause, to.Causes)
+}
+
+// Format writes the fault for the fmt verbs. %+v writes all members and the
+// causes on separate lines.
+func (to *TimeoutStruct) Format(s fmt.State, verb rune) {
+	formatFault(s, verb, to)
+}
+
+// timeout is a marker to prevent converting struct with same fields
+// into Timeout interface
+func (to *TimeoutStruct) timeout() {
+}
+
+// GetOperation retrieves the operation that did not complete in time
+func (to *TimeoutStruct) GetOperation() string {
+	return to.Operation
+}
+
+// SetOperation sets the operation that did not complete in time
+func (to *TimeoutStruct) SetOperation(operation string) {
+	to.Operation = operation
+}
+
+// MarshalJSON writes Timeout as JSON
+func (to *TimeoutStruct) MarshalJSON() ([]byte, error) {
+	return marshal(to)
+}
+
+// encode writes the Kind first and then the members directly
+func (to *TimeoutStruct) encode(e *polymorphic.Encoder) error {
+	err := beginFault(e, "Timeout")
+	if err != nil {
+		return err
+	}
+	err = to.RuntimeFaultStruct.encodeMembers(e)
+	if err != nil {
+		return err
+	}
+	e.Name("Operation")
+	e.String(to.Operation)
+	e.EndObject()
+	return nil
+}
+
+// UnmarshalJSON reads a Timeout from JSON
+func (to *TimeoutStruct) UnmarshalJSON(in []byte) error {
+	return to.unmarshal(in, false)
+}
+
+func (to *TimeoutStruct) unmarshal(in []byte, strict bool) error {
+	pxy := &struct {
+		Kind          string
+		SchemaVersion int
+		Message       string
+		MessageKey    string
+		MessageArgs   []polymorphic.MessageArg
+		Cause         FaultField
+		Causes        FaultsField
+		Operation     string
+	}{}
+	pxy.Cause.strict = strict
+	pxy.Causes.strict = strict
+	err := unmarshalProxy(in, pxy, strict)
+	if err != nil {
+		return err
+	}
+	err = checkKind(pxy.Kind, "Timeout", strict)
+	if err != nil {
+		return err
+	}
+	to.Message = pxy.Message
+	to.MessageKey = pxy.MessageKey
+	to.MessageArgs = pxy.MessageArgs
+	to.Cause = pxy.Cause.Fault
+	to.Causes = pxy.Causes.Faults
+	to.Operation = pxy.Operation
+	return nil
+}
+
+// TimeoutField type allows reading polymorphic Timeout fields
+type TimeoutField struct {
+	Timeout
+}
+
+var _ Fault = &TimeoutField{}
+var _ json.Unmarshaler = &TimeoutField{}
+
+// UnmarshalJSON reads the embedded fault taking care of the discriminator
+func (ff *TimeoutField) UnmarshalJSON(in []byte) error {
+	var err error
+	ff.Timeout, err = UnmarshalTimeout(in)
+	return err
+}
+
+// Error returns the error of the Timeout or "<nil>" when the field is null. The
+// one promoted from the interface fails on null fields.
+func (ff TimeoutField) Error() string {
+	return fieldError(ff.Timeout)
+}
+
+// Unwrap returns the Timeout so errors.As finds it through the field
+func (ff TimeoutField) Unwrap() error {
+	return unwrapCause(ff.Timeout)
+}
+
+// UnmarshalTimeout reads Timeout or its subclasses from JSON bytes
+func UnmarshalTimeout(in []byte) (Timeout, error) {
+	fault, err := UnmarshalFault(in)
+	if err != nil {
+		return nil, err
+	}
+	if timeout, ok := fault.(Timeout); ok {
+		return timeout, nil
+	}
+	return nil, fmt.Errorf("cannot unmarshal Timeout from %v", fault)
+}